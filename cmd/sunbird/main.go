@@ -5,11 +5,24 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"sunbird/internal/ast"
+	"sunbird/internal/compiler"
+	"sunbird/internal/diagnostic"
 	"sunbird/internal/evaluator"
 	"sunbird/internal/lexer"
 	"sunbird/internal/object"
 	"sunbird/internal/parser"
 	"sunbird/internal/repl"
+	"sunbird/internal/resolver"
+	"sunbird/internal/vm"
+)
+
+var (
+	diagnosticsFormat = flag.String("diagnostics", "text", "format of error output: text or json")
+	engine            = flag.String("engine", "tree", "how programs are run: tree (walk the syntax tree) or vm (compile to bytecode)")
+	searchPath        = flag.String("path", os.Getenv("SUNBIRD_PATH"),
+		"list of directories searched for imported modules (default $SUNBIRD_PATH)")
 )
 
 func init() {
@@ -23,11 +36,21 @@ func init() {
 func main() {
 	flag.Parse()
 
+	if *diagnosticsFormat != "text" && *diagnosticsFormat != "json" {
+		fmt.Printf("Error: unknown diagnostics format %q, expected text or json\n", *diagnosticsFormat)
+		os.Exit(2)
+	}
+
+	if *engine != "tree" && *engine != "vm" {
+		fmt.Printf("Error: unknown engine %q, expected tree or vm\n", *engine)
+		os.Exit(2)
+	}
+
 	args := flag.Args()
 	if len(args) == 0 {
 		fmt.Println("Welcome to the sunbird programming language!")
 		fmt.Printf("Type in 'exit' to exit.\n")
-		repl.Start(os.Stdin, os.Stdout)
+		repl.Start(os.Stdin, os.Stdout, *engine)
 
 		os.Exit(0)
 	}
@@ -50,22 +73,31 @@ func main() {
 			os.Exit(1)
 		}
 
-		l := lexer.New(string(content))
+		l := lexer.New(args[0], string(content))
 		p := parser.New(l)
 
 		program := p.ParseProgram()
 
-		if len(p.Errors()) != 0 {
-			fmt.Println("Parser errors:")
-			for _, msg := range p.Errors() {
-				fmt.Printf("\t%s\n", msg)
-			}
+		if len(p.Diagnostics()) != 0 {
+			reportDiagnostics(args[0], string(content), p.Diagnostics())
 			os.Exit(1)
 		}
 
-		env := object.NewEnvironment()
+		r := resolver.New(evaluator.IsBuiltin)
+		if errs := r.Resolve(program); len(errs) != 0 {
+			reportDiagnostics(args[0], string(content), r.Diagnostics())
+			os.Exit(1)
+		}
+
+		runtime := object.NewRuntime()
+		runtime.SearchPath = filepath.SplitList(*searchPath)
 
-		evaluated := evaluator.Eval(program, env)
+		evaluated := run(program, runtime)
+
+		if err, ok := evaluated.(*object.Error); ok {
+			reportDiagnostics(args[0], string(content), []diagnostic.Diagnostic{err.Diagnostic()})
+			os.Exit(1)
+		}
 
 		if evaluated != nil {
 			fmt.Println(evaluated.Inspect())
@@ -74,3 +106,30 @@ func main() {
 		os.Exit(0)
 	}
 }
+
+// run runs a program with the engine chosen with the --engine flag.
+func run(program *ast.Program, runtime *object.Runtime) object.Object {
+	if *engine == "tree" {
+		return evaluator.Eval(program, object.NewModuleEnvironment(runtime))
+	}
+
+	c := compiler.New()
+	if err := c.Compile(program); err != nil {
+		return err
+	}
+
+	return vm.New(c.Bytecode(), runtime).Run()
+}
+
+// reportDiagnostics prints the diagnostics to stderr in the format chosen with
+// the --diagnostics flag.
+func reportDiagnostics(filename, src string, diagnostics []diagnostic.Diagnostic) {
+	if *diagnosticsFormat == "json" {
+		_ = diagnostic.WriteJSON(os.Stderr, diagnostics)
+		return
+	}
+
+	renderer := diagnostic.NewRenderer(os.Stderr, diagnostic.UseColor(os.Stderr))
+	renderer.AddFile(filename, src)
+	renderer.Render(diagnostics)
+}
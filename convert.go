@@ -0,0 +1,516 @@
+package sunbird
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"sunbird/internal/evaluator"
+	"sunbird/internal/object"
+)
+
+var (
+	objectType = reflect.TypeOf((*object.Object)(nil)).Elem()
+	errorType  = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// fromGo converts a Go value to a sunbird value.
+func fromGo(v reflect.Value) (object.Object, error) {
+	if !v.IsValid() {
+		return evaluator.NULL, nil
+	}
+
+	if v.Type().Implements(objectType) && !(v.Kind() == reflect.Pointer && v.IsNil()) {
+		return v.Interface().(object.Object), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return evaluator.TRUE, nil
+		}
+
+		return evaluator.FALSE, nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &object.Integer{Value: v.Int()}, nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt64 {
+			return nil, fmt.Errorf("%d overflows integer", v.Uint())
+		}
+
+		return &object.Integer{Value: int64(v.Uint())}, nil
+
+	case reflect.Float32, reflect.Float64:
+		return &object.Float{Value: v.Float()}, nil
+
+	case reflect.String:
+		return &object.String{Value: v.String()}, nil
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return evaluator.NULL, nil
+		}
+
+		elements := make([]object.Object, v.Len())
+		for n := range elements {
+			element, err := fromGo(v.Index(n))
+			if err != nil {
+				return nil, err
+			}
+
+			elements[n] = element
+		}
+
+		return &object.Array{Elements: elements}, nil
+
+	case reflect.Map:
+		if v.IsNil() {
+			return evaluator.NULL, nil
+		}
+
+		return mapFromGo(v)
+
+	case reflect.Struct:
+		return structFromGo(v)
+
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return evaluator.NULL, nil
+		}
+
+		return fromGo(v.Elem())
+
+	case reflect.Func:
+		if v.IsNil() {
+			return evaluator.NULL, nil
+		}
+
+		return builtinFromGo(v), nil
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// mapFromGo converts a map to a hash, whose entries are sorted by key since
+// maps have no order.
+func mapFromGo(v reflect.Value) (object.Object, error) {
+	type entry struct {
+		key   object.Hashable
+		value object.Object
+	}
+
+	entries := []entry{}
+
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := fromGo(iter.Key())
+		if err != nil {
+			return nil, err
+		}
+
+		hashable, ok := key.(object.Hashable)
+		if !ok {
+			return nil, fmt.Errorf("unusable as hash key: %s", key.Type())
+		}
+
+		value, err := fromGo(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry{hashable, value})
+	}
+
+	sort.Slice(entries, func(a, b int) bool {
+		return entries[a].key.Inspect() < entries[b].key.Inspect()
+	})
+
+	hash := object.NewHash()
+	for _, entry := range entries {
+		hash.Set(entry.key, entry.value)
+	}
+
+	return hash, nil
+}
+
+func structFromGo(v reflect.Value) (object.Object, error) {
+	hash := object.NewHash()
+
+	for n := 0; n < v.NumField(); n++ {
+		name, ok := fieldName(v.Type().Field(n))
+		if !ok {
+			continue
+		}
+
+		value, err := fromGo(v.Field(n))
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+
+		hash.Set(&object.String{Value: name}, value)
+	}
+
+	return hash, nil
+}
+
+// fieldName returns the key of a struct field in hashes. The result is false
+// for unexported fields and fields tagged "-".
+func fieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+
+	switch tag := field.Tag.Get("sunbird"); tag {
+	case "-":
+		return "", false
+	case "":
+		return field.Name, true
+	default:
+		return tag, true
+	}
+}
+
+// builtinFromGo wraps a Go function in a builtin.
+func builtinFromGo(fn reflect.Value) *object.Builtin {
+	t := fn.Type()
+
+	return &object.Builtin{Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+		params := t.NumIn()
+		if t.IsVariadic() {
+			params--
+		}
+
+		if len(args) < params || len(args) > params && !t.IsVariadic() {
+			return &object.Error{
+				Kind:    object.TypeError,
+				Message: fmt.Sprintf("wrong number of arguments. got=%d, want=%d", len(args), params),
+			}
+		}
+
+		in := make([]reflect.Value, len(args))
+		for n, arg := range args {
+			paramType := t.In(min(n, t.NumIn()-1))
+			if t.IsVariadic() && n >= params {
+				paramType = paramType.Elem()
+			}
+
+			value, err := toValue(rt, arg, paramType)
+			if err != nil {
+				return &object.Error{Kind: object.TypeError, Message: fmt.Sprintf("argument %d: %s", n+1, err)}
+			}
+
+			in[n] = value
+		}
+
+		return resultsFromGo(fn.Call(in))
+	}}
+}
+
+// resultsFromGo converts the results of a Go function to a single value. A
+// last result of type error is returned as a runtime error.
+func resultsFromGo(results []reflect.Value) object.Object {
+	if n := len(results); n > 0 && results[n-1].Type() == errorType {
+		if err, _ := results[n-1].Interface().(error); err != nil {
+			return &object.Error{Kind: object.GenericError, Message: err.Error()}
+		}
+
+		results = results[:n-1]
+	}
+
+	if len(results) == 0 {
+		return evaluator.NULL
+	}
+
+	objs := make([]object.Object, len(results))
+	for n, result := range results {
+		obj, err := fromGo(result)
+		if err != nil {
+			return &object.Error{Kind: object.TypeError, Message: err.Error()}
+		}
+
+		objs[n] = obj
+	}
+
+	if len(objs) == 1 {
+		return objs[0]
+	}
+
+	return &object.Array{Elements: objs}
+}
+
+// toGo converts a sunbird value to the Go value Get documents. It fails for
+// a hash with two keys that are shown the same, like 1 and "1".
+func toGo(rt *object.Runtime, obj object.Object) (interface{}, error) {
+	return convertToGo(rt, obj, nil)
+}
+
+// convertToGo converts obj like toGo. seen holds the arrays, hashes and
+// instances being converted: one met again inside itself is converted to the
+// string its Inspect method shows it as, [...] or {...}.
+func convertToGo(rt *object.Runtime, obj object.Object, seen map[object.Object]bool) (interface{}, error) {
+	switch obj.(type) {
+	case *object.Array, *object.Hash, *object.Instance, *object.ClassInstance:
+		if seen[obj] {
+			if _, ok := obj.(*object.Array); ok {
+				return "[...]", nil
+			}
+
+			return "{...}", nil
+		}
+
+		if seen == nil {
+			seen = map[object.Object]bool{}
+		}
+
+		seen[obj] = true
+		defer delete(seen, obj)
+	}
+
+	switch obj := obj.(type) {
+	case nil, *object.Null:
+		return nil, nil
+
+	case *object.Boolean:
+		return obj.Value, nil
+
+	case *object.Integer:
+		return obj.Value, nil
+
+	case *object.Float:
+		return obj.Value, nil
+
+	case *object.String:
+		return obj.Value, nil
+
+	case *object.Array:
+		elements := make([]interface{}, len(obj.Elements))
+		for n, element := range obj.Elements {
+			value, err := convertToGo(rt, element, seen)
+			if err != nil {
+				return nil, err
+			}
+
+			elements[n] = value
+		}
+
+		return elements, nil
+
+	case *object.Hash:
+		m := make(map[string]interface{}, obj.Len())
+		for _, pair := range obj.Entries() {
+			key := pair.Key.Inspect()
+			if _, ok := m[key]; ok {
+				return nil, fmt.Errorf("hash has several keys shown as %q", key)
+			}
+
+			value, err := convertToGo(rt, pair.Value, seen)
+			if err != nil {
+				return nil, err
+			}
+
+			m[key] = value
+		}
+
+		return m, nil
+
+	case *object.Instance:
+		m := make(map[string]interface{}, len(obj.Fields))
+		for n, field := range obj.Struct.Fields {
+			value, err := convertToGo(rt, obj.Fields[n], seen)
+			if err != nil {
+				return nil, err
+			}
+
+			m[field] = value
+		}
+
+		return m, nil
+
+	case *object.ClassInstance:
+		return convertToGo(rt, obj.Fields, seen)
+
+	case *object.Function, *object.Builtin, *object.BoundMethod, *object.Class:
+		return func(args ...interface{}) (interface{}, error) {
+			return callFunction(rt, obj, args)
+		}, nil
+
+	case *object.ErrorValue:
+		return newError(obj.Error), nil
+	}
+
+	return obj.Inspect(), nil
+}
+
+// toValue converts a sunbird value to a Go value of type t.
+func toValue(rt *object.Runtime, obj object.Object, t reflect.Type) (reflect.Value, error) {
+	if t == objectType {
+		return reflect.ValueOf(&obj).Elem(), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Interface:
+		value, err := toGo(rt, obj)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		if value == nil {
+			return reflect.Zero(t), nil
+		}
+
+		if reflect.TypeOf(value).AssignableTo(t) {
+			return reflect.ValueOf(value).Convert(t), nil
+		}
+
+	case reflect.Bool:
+		if b, ok := obj.(*object.Boolean); ok {
+			return reflect.ValueOf(b.Value).Convert(t), nil
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, ok := obj.(*object.Integer); ok {
+			value := reflect.New(t).Elem()
+			if value.OverflowInt(i.Value) {
+				return value, fmt.Errorf("%d overflows %s", i.Value, t)
+			}
+
+			value.SetInt(i.Value)
+
+			return value, nil
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if i, ok := obj.(*object.Integer); ok {
+			value := reflect.New(t).Elem()
+			if i.Value < 0 || value.OverflowUint(uint64(i.Value)) {
+				return value, fmt.Errorf("%d overflows %s", i.Value, t)
+			}
+
+			value.SetUint(uint64(i.Value))
+
+			return value, nil
+		}
+
+	case reflect.Float32, reflect.Float64:
+		switch number := obj.(type) {
+		case *object.Float:
+			return reflect.ValueOf(number.Value).Convert(t), nil
+		case *object.Integer:
+			return reflect.ValueOf(float64(number.Value)).Convert(t), nil
+		}
+
+	case reflect.String:
+		if s, ok := obj.(*object.String); ok {
+			return reflect.ValueOf(s.Value).Convert(t), nil
+		}
+
+	case reflect.Slice:
+		if obj == evaluator.NULL {
+			return reflect.Zero(t), nil
+		}
+
+		if array, ok := obj.(*object.Array); ok {
+			slice := reflect.MakeSlice(t, len(array.Elements), len(array.Elements))
+			for n, element := range array.Elements {
+				value, err := toValue(rt, element, t.Elem())
+				if err != nil {
+					return slice, fmt.Errorf("element %d: %w", n, err)
+				}
+
+				slice.Index(n).Set(value)
+			}
+
+			return slice, nil
+		}
+
+	case reflect.Map:
+		if obj == evaluator.NULL {
+			return reflect.Zero(t), nil
+		}
+
+		if hash, ok := obj.(*object.Hash); ok {
+			m := reflect.MakeMapWithSize(t, hash.Len())
+			for _, pair := range hash.Entries() {
+				key, err := toValue(rt, pair.Key, t.Key())
+				if err != nil {
+					return m, fmt.Errorf("key %s: %w", pair.Key.Inspect(), err)
+				}
+
+				value, err := toValue(rt, pair.Value, t.Elem())
+				if err != nil {
+					return m, fmt.Errorf("key %s: %w", pair.Key.Inspect(), err)
+				}
+
+				m.SetMapIndex(key, value)
+			}
+
+			return m, nil
+		}
+
+	case reflect.Struct:
+		switch obj := obj.(type) {
+		case *object.Hash:
+			return structToValue(rt, obj, t)
+		case *object.Instance:
+			return structToValue(rt, instanceToHash(obj), t)
+		case *object.ClassInstance:
+			return structToValue(rt, obj.Fields, t)
+		}
+
+	case reflect.Pointer:
+		if obj == evaluator.NULL {
+			return reflect.Zero(t), nil
+		}
+
+		value, err := toValue(rt, obj, t.Elem())
+		if err != nil {
+			return value, err
+		}
+
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(value)
+
+		return ptr, nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("cannot use %s as %s", obj.Type(), t)
+}
+
+// instanceToHash returns a hash of the fields of an instance.
+func instanceToHash(instance *object.Instance) *object.Hash {
+	hash := object.NewHash()
+	for n, field := range instance.Struct.Fields {
+		hash.Set(&object.String{Value: field}, instance.Fields[n])
+	}
+
+	return hash
+}
+
+// structToValue fills a struct from the entries of a hash named after its
+// fields. Fields missing from the hash keep their zero value.
+func structToValue(rt *object.Runtime, hash *object.Hash, t reflect.Type) (reflect.Value, error) {
+	value := reflect.New(t).Elem()
+
+	for n := 0; n < t.NumField(); n++ {
+		name, ok := fieldName(t.Field(n))
+		if !ok {
+			continue
+		}
+
+		pair, ok := hash.Get(&object.String{Value: name})
+		if !ok {
+			continue
+		}
+
+		field, err := toValue(rt, pair, t.Field(n).Type)
+		if err != nil {
+			return value, fmt.Errorf("field %s: %w", name, err)
+		}
+
+		value.Field(n).Set(field)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,77 @@
+package sunbird
+
+import (
+	"errors"
+	"fmt"
+	"sunbird/internal/diagnostic"
+	"sunbird/internal/object"
+)
+
+// SyntaxError is the kind of the errors in the syntax of a program.
+const SyntaxError = "SyntaxError"
+
+// Error is an error of a program, with the position it happened at.
+type Error struct {
+	Kind    string // like TypeError, or Error for errors without a kind
+	Message string
+	Line    int
+	Column  int
+	Stack   []Frame // the calls the error unwound, innermost first
+}
+
+// Frame is a function call on the stack of an error.
+type Frame struct {
+	Function string
+	Line     int
+	Column   int
+}
+
+func (e *Error) Error() string {
+	msg := e.Kind + ": " + e.Message
+	if e.Line == 0 {
+		return msg
+	}
+
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, msg)
+}
+
+func newError(err *object.Error) *Error {
+	e := &Error{Kind: err.Kind, Message: err.Message, Line: err.Pos.Line, Column: err.Pos.Col}
+	if e.Kind == "" {
+		e.Kind = object.GenericError
+	}
+
+	for _, frame := range err.Stack {
+		e.Stack = append(e.Stack, Frame{Function: frame.Function, Line: frame.Pos.Line, Column: frame.Pos.Col})
+	}
+
+	return e
+}
+
+func syntaxErrors(diagnostics []diagnostic.Diagnostic) error {
+	errs := make([]error, len(diagnostics))
+	for n, d := range diagnostics {
+		errs[n] = &Error{Kind: SyntaxError, Message: d.Message, Line: d.Span.Start.Line, Column: d.Span.Start.Col}
+	}
+
+	return joinErrors(errs)
+}
+
+func runtimeErrors(objs []*object.Error) error {
+	errs := make([]error, len(objs))
+	for n, err := range objs {
+		errs[n] = newError(err)
+	}
+
+	return joinErrors(errs)
+}
+
+// joinErrors returns a single error as is, so that callers can use it as an
+// *Error without errors.As.
+func joinErrors(errs []error) error {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+
+	return errors.Join(errs...)
+}
@@ -5,21 +5,33 @@ import (
 	"sunbird/internal/token"
 )
 
+// AssignStatement assigns to an lvalue, which is an *Identifier, an
+// *IndexExpression or a *MemberExpression. Operator is "=" or a compound operator such as "+=".
 type AssignStatement struct {
 	Statement
-	Token token.Token
-	Name  *Identifier
-	Value Expression
+	Token    token.Token
+	Target   Expression
+	Operator string
+	Value    Expression
 }
 
 func (as *AssignStatement) statementNode()       {}
 func (as *AssignStatement) TokenLiteral() string { return as.Token.Literal }
+func (as *AssignStatement) Pos() token.Position  { return as.Token.Pos }
+
+func (as *AssignStatement) End() token.Position {
+	if as.Value == nil {
+		return as.Token.End
+	}
+
+	return as.Value.End()
+}
 
 func (as *AssignStatement) String() string {
 	var out bytes.Buffer
 
-	out.WriteString(as.Name.String())
-	out.WriteString(" = ")
+	out.WriteString(as.Target.String())
+	out.WriteString(" " + as.Operator + " ")
 
 	if as.Value != nil {
 		out.WriteString(as.Value.String())
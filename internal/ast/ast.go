@@ -1,8 +1,12 @@
 package ast
 
+import "sunbird/internal/token"
+
 type Node interface {
 	TokenLiteral() string
 	String() string
+	Pos() token.Position // position of the first character of the node
+	End() token.Position // position immediately after the node
 }
 
 type Statement interface {
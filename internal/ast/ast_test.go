@@ -1,6 +1,7 @@
 package ast_test
 
 import (
+	"strings"
 	"sunbird/internal/ast"
 	"sunbird/internal/token"
 	"testing"
@@ -27,3 +28,50 @@ func TestString(t *testing.T) {
 		t.Errorf("program.String() wrong. got=%q", program.String())
 	}
 }
+
+func TestInspect(t *testing.T) {
+	x := &ast.Identifier{Token: token.Token{Type: token.Ident, Literal: "x"}, Value: "x"}
+	y := &ast.Identifier{Token: token.Token{Type: token.Ident, Literal: "y"}, Value: "y"}
+
+	program := &ast.Program{
+		Statements: []ast.Statement{
+			&ast.ExpressionStatement{
+				Expression: &ast.FunctionLiteral{
+					Parameters: []ast.Pattern{x},
+					Body: &ast.BlockStatement{
+						Statements: []ast.Statement{
+							&ast.ReturnStatement{
+								ReturnValue: &ast.InfixExpression{Left: x, Operator: "+", Right: y},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var names []string
+
+	ast.Inspect(program, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Identifier); ok {
+			names = append(names, ident.Value)
+		}
+
+		return true
+	})
+
+	if strings.Join(names, " ") != "x x y" {
+		t.Errorf("wrong identifiers visited. got=%q", names)
+	}
+
+	visited := 0
+	ast.Inspect(program, func(node ast.Node) bool {
+		visited++
+		_, isFunction := node.(*ast.FunctionLiteral)
+		return !isFunction
+	})
+
+	if visited != 3 {
+		t.Errorf("wrong number of nodes visited. expected=3, got=%d", visited)
+	}
+}
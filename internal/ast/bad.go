@@ -0,0 +1,27 @@
+package ast
+
+import "sunbird/internal/token"
+
+// BadExpression is a placeholder for an expression containing syntax errors.
+type BadExpression struct {
+	Token    token.Token // the first token of the expression
+	From, To token.Position
+}
+
+func (be *BadExpression) expressionNode()      {}
+func (be *BadExpression) TokenLiteral() string { return be.Token.Literal }
+func (be *BadExpression) Pos() token.Position  { return be.From }
+func (be *BadExpression) End() token.Position  { return be.To }
+func (be *BadExpression) String() string       { return "<bad expression>" }
+
+// BadStatement is a placeholder for a statement containing syntax errors.
+type BadStatement struct {
+	Token    token.Token // the first token of the statement
+	From, To token.Position
+}
+
+func (bs *BadStatement) statementNode()       {}
+func (bs *BadStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BadStatement) Pos() token.Position  { return bs.From }
+func (bs *BadStatement) End() token.Position  { return bs.To }
+func (bs *BadStatement) String() string       { return "<bad statement>" }
@@ -6,12 +6,27 @@ import (
 )
 
 type BlockStatement struct {
-	Token      token.Token
+	Token      token.Token // the '{' token
 	Statements []Statement
+	Rbrace     token.Token // the closing '}'
 }
 
 func (bs *BlockStatement) statementNode()       {}
 func (bs *BlockStatement) TokenLiteral() string { return bs.Token.Literal }
+func (bs *BlockStatement) Pos() token.Position  { return bs.Token.Pos }
+
+func (bs *BlockStatement) End() token.Position {
+	// blocks made up by the parser, like the one holding an else if, have no
+	// braces of their own
+	if !bs.Rbrace.End.IsValid() && len(bs.Statements) > 0 {
+		last := bs.Statements[len(bs.Statements)-1]
+		if last != nil {
+			return last.End()
+		}
+	}
+
+	return bs.Rbrace.End
+}
 
 func (bs *BlockStatement) String() string {
 	var out bytes.Buffer
@@ -0,0 +1,60 @@
+package ast
+
+import (
+	"strings"
+	"sunbird/internal/token"
+)
+
+// ClassDefinition is the class declared by a class statement, which the
+// parser turns into a var statement named after the class like it does for
+// structs.
+type ClassDefinition struct {
+	Token      token.Token // the 'class' token
+	Name       *Identifier
+	Superclass Expression         // nil without extends
+	Methods    []*FunctionLiteral // their token is the name of the method
+	Rbrace     token.Token
+}
+
+func (cd *ClassDefinition) expressionNode()      {}
+func (cd *ClassDefinition) TokenLiteral() string { return cd.Token.Literal }
+func (cd *ClassDefinition) Pos() token.Position  { return cd.Token.Pos }
+func (cd *ClassDefinition) End() token.Position  { return cd.Rbrace.End }
+
+func (cd *ClassDefinition) String() string {
+	var out strings.Builder
+
+	out.WriteString("class " + cd.Name.String())
+
+	if cd.Superclass != nil {
+		out.WriteString(" extends " + cd.Superclass.String())
+	}
+
+	out.WriteString(" {")
+
+	for _, method := range cd.Methods {
+		out.WriteString(" " + method.String())
+	}
+
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+// SuperExpression looks up a method of the superclass bound to self, like
+// super.init.
+type SuperExpression struct {
+	Token  token.Token // the 'super' token
+	Method *Identifier
+
+	// the variables holding the superclass and the instance, bound by the
+	// resolver
+	Class *Identifier
+	Self  *Identifier
+}
+
+func (se *SuperExpression) expressionNode()      {}
+func (se *SuperExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SuperExpression) Pos() token.Position  { return se.Token.Pos }
+func (se *SuperExpression) End() token.Position  { return se.Method.End() }
+func (se *SuperExpression) String() string       { return "super." + se.Method.String() }
@@ -9,6 +9,15 @@ type ExpressionStatement struct {
 
 func (es *ExpressionStatement) statementNode()       {}
 func (es *ExpressionStatement) TokenLiteral() string { return es.Token.Literal }
+func (es *ExpressionStatement) Pos() token.Position  { return es.Token.Pos }
+
+func (es *ExpressionStatement) End() token.Position {
+	if es.Expression == nil {
+		return es.Token.End
+	}
+
+	return es.Expression.End()
+}
 
 func (es *ExpressionStatement) String() string {
 	if es.Expression != nil {
@@ -15,6 +15,15 @@ type ForStatement struct {
 
 func (fs *ForStatement) statementNode()       {}
 func (fs *ForStatement) TokenLiteral() string { return fs.Token.Literal }
+func (fs *ForStatement) Pos() token.Position  { return fs.Token.Pos }
+
+func (fs *ForStatement) End() token.Position {
+	if fs.Body == nil {
+		return fs.Token.End
+	}
+
+	return fs.Body.End()
+}
 
 func (fs *ForStatement) String() string {
 	var out bytes.Buffer
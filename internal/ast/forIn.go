@@ -0,0 +1,43 @@
+package ast
+
+import (
+	"bytes"
+	"sunbird/internal/token"
+)
+
+type ForInStatement struct {
+	Token    token.Token
+	Index    *Identifier // optional, the key or index of the current element
+	Item     *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fs *ForInStatement) statementNode()       {}
+func (fs *ForInStatement) TokenLiteral() string { return fs.Token.Literal }
+func (fs *ForInStatement) Pos() token.Position  { return fs.Token.Pos }
+
+func (fs *ForInStatement) End() token.Position {
+	if fs.Body == nil {
+		return fs.Token.End
+	}
+
+	return fs.Body.End()
+}
+
+func (fs *ForInStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for ")
+	if fs.Index != nil {
+		out.WriteString(fs.Index.String())
+		out.WriteString(", ")
+	}
+	out.WriteString(fs.Item.String())
+	out.WriteString(" in ")
+	out.WriteString(fs.Iterable.String())
+	out.WriteString(" ")
+	out.WriteString(fs.Body.String())
+
+	return out.String()
+}
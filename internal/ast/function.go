@@ -8,12 +8,21 @@ import (
 
 type FunctionLiteral struct {
 	Token      token.Token
-	Parameters []*Identifier
+	Parameters []Pattern // names, or patterns destructuring the arguments
 	Body       *BlockStatement
 }
 
 func (fl *FunctionLiteral) expressionNode()      {}
 func (fl *FunctionLiteral) TokenLiteral() string { return fl.Token.Literal }
+func (fl *FunctionLiteral) Pos() token.Position  { return fl.Token.Pos }
+
+func (fl *FunctionLiteral) End() token.Position {
+	if fl.Body == nil {
+		return fl.Token.End
+	}
+
+	return fl.Body.End()
+}
 
 func (fl *FunctionLiteral) String() string {
 	var out bytes.Buffer
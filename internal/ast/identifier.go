@@ -3,10 +3,29 @@ package ast
 import "sunbird/internal/token"
 
 type Identifier struct {
-	Token token.Token
-	Value string
+	Token   token.Token
+	Value   string
+	Binding Binding // filled in by the resolver
 }
 
 func (i *Identifier) expressionNode()      {}
 func (i *Identifier) TokenLiteral() string { return i.Token.Literal }
+func (i *Identifier) Pos() token.Position  { return i.Token.Pos }
+func (i *Identifier) End() token.Position  { return i.Token.End }
 func (i *Identifier) String() string       { return i.Value }
+
+type BindingKind uint8
+
+const (
+	Unresolved BindingKind = iota
+	Variable
+	Builtin
+)
+
+// Binding locates the variable an identifier refers to among the
+// environments of the running program.
+type Binding struct {
+	Kind  BindingKind
+	Depth int // how many environments out from the current one
+	Slot  int // the index of the variable in that environment
+}
@@ -14,6 +14,18 @@ type IfExpression struct {
 
 func (ie *IfExpression) expressionNode()      {}
 func (ie *IfExpression) TokenLiteral() string { return ie.Token.Literal }
+func (ie *IfExpression) Pos() token.Position  { return ie.Token.Pos }
+
+func (ie *IfExpression) End() token.Position {
+	switch {
+	case ie.Alternative != nil:
+		return ie.Alternative.End()
+	case ie.Consequence != nil:
+		return ie.Consequence.End()
+	default:
+		return ie.Token.End
+	}
+}
 
 func (ie *IfExpression) String() string {
 	var out bytes.Buffer
@@ -0,0 +1,82 @@
+package ast
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"sunbird/internal/token"
+)
+
+// ImportStatement is either `import "path"`, optionally followed by
+// `as name`, binding the whole module, or `import { a, b } from "path"`
+// binding some of its exports.
+type ImportStatement struct {
+	Token token.Token // the 'import' token
+	Path  *StringLiteral
+	Alias *Identifier   // the name given with as, if any
+	Names []*Identifier // the names imported between braces, if any
+
+	// Module is the variable the whole module is bound to, the alias or one
+	// named after the module, filled in by the resolver
+	Module *Identifier
+}
+
+func (is *ImportStatement) statementNode()       {}
+func (is *ImportStatement) TokenLiteral() string { return is.Token.Literal }
+func (is *ImportStatement) Pos() token.Position  { return is.Token.Pos }
+
+func (is *ImportStatement) End() token.Position {
+	if is.Alias != nil {
+		return is.Alias.End()
+	}
+
+	return is.Path.End()
+}
+
+func (is *ImportStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("import ")
+
+	if is.Names != nil {
+		names := []string{}
+		for _, name := range is.Names {
+			names = append(names, name.String())
+		}
+
+		out.WriteString("{ " + strings.Join(names, ", ") + " } from ")
+	}
+
+	out.WriteString(strconv.Quote(is.Path.Value))
+
+	if is.Alias != nil {
+		out.WriteString(" as " + is.Alias.String())
+	}
+
+	out.WriteString(";")
+
+	return out.String()
+}
+
+// ExportStatement declares a variable that modules importing this one can
+// use.
+type ExportStatement struct {
+	Token     token.Token // the 'export' token
+	Statement *VarStatement
+}
+
+func (es *ExportStatement) statementNode()       {}
+func (es *ExportStatement) TokenLiteral() string { return es.Token.Literal }
+func (es *ExportStatement) Pos() token.Position  { return es.Token.Pos }
+
+func (es *ExportStatement) End() token.Position {
+	if es.Statement == nil {
+		return es.Token.End
+	}
+
+	return es.Statement.End()
+}
+
+func (es *ExportStatement) String() string {
+	return es.TokenLiteral() + " " + es.Statement.String()
+}
@@ -14,6 +14,15 @@ type InfixExpression struct {
 
 func (oe *InfixExpression) expressionNode()      {}
 func (oe *InfixExpression) TokenLiteral() string { return oe.Token.Literal }
+func (oe *InfixExpression) Pos() token.Position  { return oe.Left.Pos() }
+
+func (oe *InfixExpression) End() token.Position {
+	if oe.Right == nil {
+		return oe.Token.End
+	}
+
+	return oe.Right.End()
+}
 
 func (oe *InfixExpression) String() string {
 	var out bytes.Buffer
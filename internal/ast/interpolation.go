@@ -0,0 +1,44 @@
+package ast
+
+import (
+	"bytes"
+	"sunbird/internal/token"
+)
+
+// InterpolatedString is a string with embedded ${...} expressions. Parts
+// alternates between *StringLiteral segments and the embedded expressions,
+// starting and ending with a segment.
+type InterpolatedString struct {
+	Token token.Token // the InterpStart token
+	Parts []Expression
+}
+
+func (is *InterpolatedString) expressionNode()      {}
+func (is *InterpolatedString) TokenLiteral() string { return is.Token.Literal }
+func (is *InterpolatedString) Pos() token.Position  { return is.Token.Pos }
+
+func (is *InterpolatedString) End() token.Position {
+	last := is.Parts[len(is.Parts)-1]
+	if last == nil {
+		return is.Token.End
+	}
+
+	return last.End()
+}
+
+func (is *InterpolatedString) String() string {
+	var out bytes.Buffer
+
+	for i, part := range is.Parts {
+		if i%2 == 0 {
+			out.WriteString(part.String())
+			continue
+		}
+
+		out.WriteString("${")
+		out.WriteString(part.String())
+		out.WriteString("}")
+	}
+
+	return out.String()
+}
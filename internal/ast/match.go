@@ -0,0 +1,52 @@
+package ast
+
+import (
+	"strings"
+	"sunbird/internal/token"
+)
+
+// MatchExpression evaluates the body of the first arm whose pattern matches
+// the subject.
+type MatchExpression struct {
+	Token   token.Token // the 'match' token
+	Subject Expression
+	Arms    []*MatchArm
+	Rbrace  token.Token
+}
+
+func (me *MatchExpression) expressionNode()      {}
+func (me *MatchExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MatchExpression) Pos() token.Position  { return me.Token.Pos }
+func (me *MatchExpression) End() token.Position  { return me.Rbrace.End }
+
+func (me *MatchExpression) String() string {
+	arms := []string{}
+	for _, arm := range me.Arms {
+		arms = append(arms, arm.String())
+	}
+
+	return "match " + me.Subject.String() + " { " + strings.Join(arms, ", ") + " }"
+}
+
+// MatchArm is a case of a match expression. An arm whose body is a single
+// expression gets a block made up by the parser, like an else if.
+type MatchArm struct {
+	Patterns []Pattern // alternatives separated by |
+	Guard    Expression
+	Body     *BlockStatement
+}
+
+func (ma *MatchArm) String() string {
+	patterns := []string{}
+	for _, pattern := range ma.Patterns {
+		patterns = append(patterns, pattern.String())
+	}
+
+	out := strings.Join(patterns, " | ")
+
+	if ma.Guard != nil {
+		out += " if " + ma.Guard.String()
+	}
+
+	return out + " => " + ma.Body.String()
+}
@@ -0,0 +1,19 @@
+package ast
+
+import "sunbird/internal/token"
+
+// MemberExpression accesses a field of a value, like e.message.
+type MemberExpression struct {
+	Token    token.Token // the '.' token
+	Object   Expression
+	Property *Identifier
+}
+
+func (me *MemberExpression) expressionNode()      {}
+func (me *MemberExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MemberExpression) Pos() token.Position  { return me.Object.Pos() }
+func (me *MemberExpression) End() token.Position  { return me.Property.End() }
+
+func (me *MemberExpression) String() string {
+	return "(" + me.Object.String() + "." + me.Property.String() + ")"
+}
@@ -8,4 +8,6 @@ type NullLiteral struct {
 
 func (nl *NullLiteral) expressionNode()      {}
 func (nl *NullLiteral) TokenLiteral() string { return nl.Token.Literal }
+func (nl *NullLiteral) Pos() token.Position  { return nl.Token.Pos }
+func (nl *NullLiteral) End() token.Position  { return nl.Token.End }
 func (nl *NullLiteral) String() string       { return nl.Token.Literal }
@@ -0,0 +1,107 @@
+package ast
+
+import (
+	"strings"
+	"sunbird/internal/token"
+)
+
+// Pattern is the shape a match arm or a declaration expects a value to have.
+// Identifiers are patterns binding the value they match.
+type Pattern interface {
+	Node
+	patternNode()
+}
+
+func (i *Identifier) patternNode() {}
+
+// LiteralPattern matches values equal to a literal.
+type LiteralPattern struct {
+	Value Expression
+}
+
+func (lp *LiteralPattern) patternNode()         {}
+func (lp *LiteralPattern) TokenLiteral() string { return lp.Value.TokenLiteral() }
+func (lp *LiteralPattern) Pos() token.Position  { return lp.Value.Pos() }
+func (lp *LiteralPattern) End() token.Position  { return lp.Value.End() }
+func (lp *LiteralPattern) String() string       { return lp.Value.String() }
+
+// WildcardPattern is _, which matches anything.
+type WildcardPattern struct {
+	Token token.Token
+}
+
+func (wp *WildcardPattern) patternNode()         {}
+func (wp *WildcardPattern) TokenLiteral() string { return wp.Token.Literal }
+func (wp *WildcardPattern) Pos() token.Position  { return wp.Token.Pos }
+func (wp *WildcardPattern) End() token.Position  { return wp.Token.End }
+func (wp *WildcardPattern) String() string       { return "_" }
+
+// ArrayPattern matches arrays of the same length whose elements match, or of
+// at least that length when the remaining elements are bound to Rest.
+type ArrayPattern struct {
+	Token    token.Token // the '[' token
+	Elements []Pattern
+	Rest     *Identifier // the name after ..., if any
+	Rbracket token.Token
+}
+
+func (ap *ArrayPattern) patternNode()         {}
+func (ap *ArrayPattern) TokenLiteral() string { return ap.Token.Literal }
+func (ap *ArrayPattern) Pos() token.Position  { return ap.Token.Pos }
+func (ap *ArrayPattern) End() token.Position  { return ap.Rbracket.End }
+
+func (ap *ArrayPattern) String() string {
+	elements := []string{}
+	for _, element := range ap.Elements {
+		elements = append(elements, element.String())
+	}
+
+	if ap.Rest != nil {
+		elements = append(elements, "..."+ap.Rest.String())
+	}
+
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// HashPattern destructures the keys of a hash, or the fields of an instance,
+// like {name, age: years}. A key on its own binds a variable of the same name,
+// which is then both the key and its value.
+type HashPattern struct {
+	Token  token.Token // the '{' token
+	Keys   []*Identifier
+	Values []Pattern // the patterns of Keys
+	Rbrace token.Token
+}
+
+func (hp *HashPattern) patternNode()         {}
+func (hp *HashPattern) TokenLiteral() string { return hp.Token.Literal }
+func (hp *HashPattern) Pos() token.Position  { return hp.Token.Pos }
+func (hp *HashPattern) End() token.Position  { return hp.Rbrace.End }
+
+func (hp *HashPattern) String() string {
+	pairs := []string{}
+	for i, key := range hp.Keys {
+		if hp.Values[i] == Pattern(key) {
+			pairs = append(pairs, key.String())
+		} else {
+			pairs = append(pairs, key.String()+": "+hp.Values[i].String())
+		}
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+
+// PatternNames returns the identifiers pattern binds, in order.
+func PatternNames(pattern Pattern) []*Identifier {
+	names := []*Identifier{}
+
+	Inspect(pattern, func(node Node) bool {
+		if ident, ok := node.(*Identifier); ok {
+			names = append(names, ident)
+		}
+
+		return true
+	})
+
+	return names
+}
@@ -13,6 +13,15 @@ type PrefixExpression struct {
 
 func (pe *PrefixExpression) expressionNode()      {}
 func (pe *PrefixExpression) TokenLiteral() string { return pe.Token.Literal }
+func (pe *PrefixExpression) Pos() token.Position  { return pe.Token.Pos }
+
+func (pe *PrefixExpression) End() token.Position {
+	if pe.Right == nil {
+		return pe.Token.End
+	}
+
+	return pe.Right.End()
+}
 
 func (pe *PrefixExpression) String() string {
 	var out bytes.Buffer
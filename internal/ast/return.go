@@ -12,6 +12,15 @@ type ReturnStatement struct {
 
 func (rs *ReturnStatement) statementNode()       {}
 func (rs *ReturnStatement) TokenLiteral() string { return rs.Token.Literal }
+func (rs *ReturnStatement) Pos() token.Position  { return rs.Token.Pos }
+
+func (rs *ReturnStatement) End() token.Position {
+	if rs.ReturnValue == nil {
+		return rs.Token.End
+	}
+
+	return rs.ReturnValue.End()
+}
 
 func (rs *ReturnStatement) String() string {
 	var out bytes.Buffer
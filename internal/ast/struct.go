@@ -0,0 +1,52 @@
+package ast
+
+import (
+	"strings"
+	"sunbird/internal/token"
+)
+
+// StructDefinition is the struct type declared by a struct statement, which
+// the parser turns into a var statement named after the struct.
+type StructDefinition struct {
+	Token  token.Token // the 'struct' token
+	Name   *Identifier
+	Fields []*Identifier
+	Rbrace token.Token
+}
+
+func (sd *StructDefinition) expressionNode()      {}
+func (sd *StructDefinition) TokenLiteral() string { return sd.Token.Literal }
+func (sd *StructDefinition) Pos() token.Position  { return sd.Token.Pos }
+func (sd *StructDefinition) End() token.Position  { return sd.Rbrace.End }
+
+func (sd *StructDefinition) String() string {
+	fields := []string{}
+	for _, field := range sd.Fields {
+		fields = append(fields, field.String())
+	}
+
+	return "struct " + sd.Name.String() + " { " + strings.Join(fields, ", ") + " }"
+}
+
+// StructLiteral creates an instance of a struct, like Point{x: 1, y: 2}.
+type StructLiteral struct {
+	Token  token.Token // the '{' token
+	Struct Expression
+	Fields []*Identifier
+	Values []Expression // the values of Fields
+	Rbrace token.Token
+}
+
+func (sl *StructLiteral) expressionNode()      {}
+func (sl *StructLiteral) TokenLiteral() string { return sl.Token.Literal }
+func (sl *StructLiteral) Pos() token.Position  { return sl.Struct.Pos() }
+func (sl *StructLiteral) End() token.Position  { return sl.Rbrace.End }
+
+func (sl *StructLiteral) String() string {
+	fields := []string{}
+	for i, field := range sl.Fields {
+		fields = append(fields, field.String()+": "+sl.Values[i].String())
+	}
+
+	return sl.Struct.String() + "{" + strings.Join(fields, ", ") + "}"
+}
@@ -0,0 +1,24 @@
+package ast
+
+import "sunbird/internal/token"
+
+type ThrowStatement struct {
+	Token token.Token // the 'throw' token
+	Value Expression
+}
+
+func (ts *ThrowStatement) statementNode()       {}
+func (ts *ThrowStatement) TokenLiteral() string { return ts.Token.Literal }
+func (ts *ThrowStatement) Pos() token.Position  { return ts.Token.Pos }
+
+func (ts *ThrowStatement) End() token.Position {
+	if ts.Value == nil {
+		return ts.Token.End
+	}
+
+	return ts.Value.End()
+}
+
+func (ts *ThrowStatement) String() string {
+	return ts.TokenLiteral() + " " + ts.Value.String() + ";"
+}
@@ -6,19 +6,36 @@ import (
 )
 
 type VarStatement struct {
-	Token token.Token
-	Name  *Identifier
-	Value Expression
+	Token   token.Token
+	Name    *Identifier
+	Pattern Pattern // replaces Name in destructuring declarations
+	Value   Expression
 }
 
 func (vs *VarStatement) statementNode()       {}
 func (vs *VarStatement) TokenLiteral() string { return vs.Token.Literal }
+func (vs *VarStatement) Pos() token.Position  { return vs.Token.Pos }
+
+func (vs *VarStatement) End() token.Position {
+	if vs.Value == nil {
+		return vs.target().End()
+	}
+
+	return vs.Value.End()
+}
 
 func (vs *VarStatement) String() string {
+	switch def := vs.Value.(type) {
+	case *StructDefinition:
+		return def.String()
+	case *ClassDefinition:
+		return def.String()
+	}
+
 	var out bytes.Buffer
 
 	out.WriteString(vs.TokenLiteral() + " ")
-	out.WriteString(vs.Name.String())
+	out.WriteString(vs.target().String())
 	out.WriteString(" = ")
 
 	if vs.Value != nil {
@@ -29,3 +46,20 @@ func (vs *VarStatement) String() string {
 
 	return out.String()
 }
+
+// Names returns the variables the statement declares.
+func (vs *VarStatement) Names() []*Identifier {
+	if vs.Pattern != nil {
+		return PatternNames(vs.Pattern)
+	}
+
+	return []*Identifier{vs.Name}
+}
+
+func (vs *VarStatement) target() Node {
+	if vs.Pattern != nil {
+		return vs.Pattern
+	}
+
+	return vs.Name
+}
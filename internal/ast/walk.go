@@ -0,0 +1,211 @@
+package ast
+
+// Inspect traverses the tree rooted at node depth first, calling f for every
+// node. The children of a node are skipped when f returns false.
+func Inspect(node Node, f func(Node) bool) {
+	if !f(node) {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		for _, stmt := range n.Statements {
+			Inspect(stmt, f)
+		}
+
+	case *BlockStatement:
+		for _, stmt := range n.Statements {
+			Inspect(stmt, f)
+		}
+
+	case *ExpressionStatement:
+		inspectExpression(n.Expression, f)
+
+	case *VarStatement:
+		if n.Pattern != nil {
+			Inspect(n.Pattern, f)
+		} else {
+			Inspect(n.Name, f)
+		}
+
+		inspectExpression(n.Value, f)
+
+	case *ExportStatement:
+		Inspect(n.Statement, f)
+
+	case *ImportStatement:
+		if n.Alias != nil {
+			Inspect(n.Alias, f)
+		}
+
+		for _, name := range n.Names {
+			Inspect(name, f)
+		}
+
+	case *AssignStatement:
+		inspectExpression(n.Target, f)
+		inspectExpression(n.Value, f)
+
+	case *ReturnStatement:
+		inspectExpression(n.ReturnValue, f)
+
+	case *ThrowStatement:
+		inspectExpression(n.Value, f)
+
+	case *TryStatement:
+		Inspect(n.Block, f)
+
+		if n.Catch != nil {
+			Inspect(n.CatchParam, f)
+			Inspect(n.Catch, f)
+		}
+
+		if n.Finally != nil {
+			Inspect(n.Finally, f)
+		}
+
+	case *WhileStatement:
+		inspectExpression(n.Condition, f)
+		Inspect(n.Body, f)
+
+	case *ForStatement:
+		if n.Init != nil {
+			Inspect(n.Init, f)
+		}
+
+		inspectExpression(n.Condition, f)
+
+		if n.Update != nil {
+			Inspect(n.Update, f)
+		}
+
+		Inspect(n.Body, f)
+
+	case *ForInStatement:
+		if n.Index != nil {
+			Inspect(n.Index, f)
+		}
+
+		Inspect(n.Item, f)
+		inspectExpression(n.Iterable, f)
+		Inspect(n.Body, f)
+
+	case *IfExpression:
+		inspectExpression(n.Condition, f)
+		Inspect(n.Consequence, f)
+
+		if n.Alternative != nil {
+			Inspect(n.Alternative, f)
+		}
+
+	case *MatchExpression:
+		inspectExpression(n.Subject, f)
+
+		for _, arm := range n.Arms {
+			for _, pattern := range arm.Patterns {
+				Inspect(pattern, f)
+			}
+
+			inspectExpression(arm.Guard, f)
+			Inspect(arm.Body, f)
+		}
+
+	case *LiteralPattern:
+		inspectExpression(n.Value, f)
+
+	case *ArrayPattern:
+		for _, element := range n.Elements {
+			Inspect(element, f)
+		}
+
+		if n.Rest != nil {
+			Inspect(n.Rest, f)
+		}
+
+	case *HashPattern:
+		// the keys are labels, or the values themselves in shorthand pairs
+		for _, value := range n.Values {
+			Inspect(value, f)
+		}
+
+	case *FunctionLiteral:
+		for _, param := range n.Parameters {
+			Inspect(param, f)
+		}
+
+		Inspect(n.Body, f)
+
+	case *CallExpression:
+		inspectExpression(n.Function, f)
+
+		for _, arg := range n.Arguments {
+			inspectExpression(arg, f)
+		}
+
+	case *InfixExpression:
+		inspectExpression(n.Left, f)
+		inspectExpression(n.Right, f)
+
+	case *PrefixExpression:
+		inspectExpression(n.Right, f)
+
+	case *IndexExpression:
+		inspectExpression(n.Left, f)
+		inspectExpression(n.Index, f)
+
+	case *MemberExpression:
+		inspectExpression(n.Object, f)
+		Inspect(n.Property, f)
+
+	case *ArrayLiteral:
+		for _, element := range n.Elements {
+			inspectExpression(element, f)
+		}
+
+	case *HashLiteral:
+		for _, pair := range n.Pairs {
+			inspectExpression(pair.Key, f)
+			inspectExpression(pair.Value, f)
+		}
+
+	case *StructDefinition:
+		Inspect(n.Name, f)
+
+		for _, field := range n.Fields {
+			Inspect(field, f)
+		}
+
+	case *ClassDefinition:
+		Inspect(n.Name, f)
+		inspectExpression(n.Superclass, f)
+
+		for _, method := range n.Methods {
+			Inspect(method, f)
+		}
+
+	case *SuperExpression:
+		Inspect(n.Class, f)
+		Inspect(n.Self, f)
+		Inspect(n.Method, f)
+
+	case *StructLiteral:
+		inspectExpression(n.Struct, f)
+
+		for i, field := range n.Fields {
+			Inspect(field, f)
+			inspectExpression(n.Values[i], f)
+		}
+
+	case *InterpolatedString:
+		for _, part := range n.Parts {
+			inspectExpression(part, f)
+		}
+	}
+}
+
+// inspectExpression inspects an optional expression.
+func inspectExpression(exp Expression, f func(Node) bool) {
+	if exp != nil {
+		Inspect(exp, f)
+	}
+}
@@ -0,0 +1,35 @@
+package ast
+
+import (
+	"bytes"
+	"sunbird/internal/token"
+)
+
+type WhileStatement struct {
+	Token     token.Token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (ws *WhileStatement) statementNode()       {}
+func (ws *WhileStatement) TokenLiteral() string { return ws.Token.Literal }
+func (ws *WhileStatement) Pos() token.Position  { return ws.Token.Pos }
+
+func (ws *WhileStatement) End() token.Position {
+	if ws.Body == nil {
+		return ws.Token.End
+	}
+
+	return ws.Body.End()
+}
+
+func (ws *WhileStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("while ")
+	out.WriteString(ws.Condition.String())
+	out.WriteString(" ")
+	out.WriteString(ws.Body.String())
+
+	return out.String()
+}
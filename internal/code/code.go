@@ -0,0 +1,112 @@
+package code
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// Instructions is a sequence of encoded instructions, each an opcode followed
+// by its big endian operands.
+type Instructions []byte
+
+// Make encodes an instruction. It returns nil for unknown opcodes.
+func Make(op Opcode, operands ...int) []byte {
+	def, ok := Lookup(op)
+	if !ok {
+		return nil
+	}
+
+	length := 1
+	for _, width := range def.OperandWidths {
+		length += width
+	}
+
+	instruction := make([]byte, length)
+	instruction[0] = byte(op)
+
+	offset := 1
+	for i, operand := range operands {
+		switch def.OperandWidths[i] {
+		case 2:
+			binary.BigEndian.PutUint16(instruction[offset:], uint16(operand))
+		case 1:
+			instruction[offset] = byte(operand)
+		}
+
+		offset += def.OperandWidths[i]
+	}
+
+	return instruction
+}
+
+// Fits reports whether the operands of op fit in the bytes its instruction
+// has for them, returning the index of the first one that does not.
+func Fits(op Opcode, operands ...int) (int, bool) {
+	def, ok := Lookup(op)
+	if !ok {
+		return 0, true
+	}
+
+	for i, operand := range operands {
+		if operand < 0 || operand >= 1<<(8*def.OperandWidths[i]) {
+			return i, false
+		}
+	}
+
+	return 0, true
+}
+
+// ReadOperands decodes the operands of an instruction, returning them with
+// the number of bytes they took.
+func ReadOperands(def *Definition, ins Instructions) ([]int, int) {
+	operands := make([]int, len(def.OperandWidths))
+	offset := 0
+
+	for i, width := range def.OperandWidths {
+		switch width {
+		case 2:
+			operands[i] = int(ReadUint16(ins[offset:]))
+		case 1:
+			operands[i] = int(ReadUint8(ins[offset:]))
+		}
+
+		offset += width
+	}
+
+	return operands, offset
+}
+
+func ReadUint16(ins Instructions) uint16 {
+	return binary.BigEndian.Uint16(ins)
+}
+
+func ReadUint8(ins Instructions) uint8 {
+	return ins[0]
+}
+
+// String disassembles the instructions, one per line.
+func (ins Instructions) String() string {
+	var out strings.Builder
+
+	for i := 0; i < len(ins); {
+		def, ok := Lookup(Opcode(ins[i]))
+		if !ok {
+			fmt.Fprintf(&out, "%04d ERROR: unknown opcode %d\n", i, ins[i])
+			i++
+			continue
+		}
+
+		operands, read := ReadOperands(def, ins[i+1:])
+
+		fmt.Fprintf(&out, "%04d %s", i, def.Name)
+		for _, operand := range operands {
+			fmt.Fprintf(&out, " %d", operand)
+		}
+		out.WriteString("\n")
+
+		i += 1 + read
+	}
+
+	return out.String()
+}
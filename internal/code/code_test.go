@@ -0,0 +1,84 @@
+package code_test
+
+import (
+	"sunbird/internal/code"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		op       code.Opcode
+		operands []int
+		expected []byte
+	}{
+		{code.OpConstant, []int{65534}, []byte{byte(code.OpConstant), 255, 254}},
+		{code.OpAdd, []int{}, []byte{byte(code.OpAdd)}},
+		{code.OpCall, []int{255}, []byte{byte(code.OpCall), 255}},
+		{code.OpIterNext, []int{1, 2, 300}, []byte{byte(code.OpIterNext), 0, 1, 2, 1, 44}},
+	}
+
+	for _, tt := range tests {
+		instruction := code.Make(tt.op, tt.operands...)
+
+		if string(instruction) != string(tt.expected) {
+			t.Errorf("wrong instruction. expected=%v, got=%v", tt.expected, instruction)
+		}
+	}
+}
+
+func TestReadOperands(t *testing.T) {
+	tests := []struct {
+		op        code.Opcode
+		operands  []int
+		bytesRead int
+	}{
+		{code.OpConstant, []int{65535}, 2},
+		{code.OpCall, []int{3}, 1},
+		{code.OpImport, []int{1, 2}, 4},
+		{code.OpIterNext, []int{7, 2, 1000}, 5},
+	}
+
+	for _, tt := range tests {
+		instruction := code.Make(tt.op, tt.operands...)
+
+		def, ok := code.Lookup(tt.op)
+		if !ok {
+			t.Fatalf("definition not found: %d", tt.op)
+		}
+
+		operands, n := code.ReadOperands(def, instruction[1:])
+		if n != tt.bytesRead {
+			t.Errorf("wrong number of bytes read. expected=%d, got=%d", tt.bytesRead, n)
+		}
+
+		for i, want := range tt.operands {
+			if operands[i] != want {
+				t.Errorf("operand %d wrong. expected=%d, got=%d", i, want, operands[i])
+			}
+		}
+	}
+}
+
+func TestInstructionsString(t *testing.T) {
+	instructions := []code.Instructions{
+		code.Make(code.OpAdd),
+		code.Make(code.OpGetLocal, 1),
+		code.Make(code.OpConstant, 65535),
+		code.Make(code.OpCall, 2),
+	}
+
+	expected := `0000 OpAdd
+0001 OpGetLocal 1
+0004 OpConstant 65535
+0007 OpCall 2
+`
+
+	var concatted code.Instructions
+	for _, ins := range instructions {
+		concatted = append(concatted, ins...)
+	}
+
+	if concatted.String() != expected {
+		t.Errorf("instructions wrongly formatted.\nexpected=%q\ngot=%q", expected, concatted.String())
+	}
+}
@@ -0,0 +1,210 @@
+package code
+
+type Opcode byte
+
+const (
+	// OpConstant pushes the constant at index.
+	OpConstant Opcode = iota
+	OpNull
+	OpTrue
+	OpFalse
+
+	OpPop
+	OpDup  // duplicates the top of the stack
+	OpDup2 // duplicates the two values on top of the stack
+
+	// Binary operators pop the right operand, then the left one
+	OpAdd
+	OpSub
+	OpMul
+	OpDiv
+	OpMod
+	OpFloorDiv
+	OpPow
+	OpBitAnd
+	OpBitOr
+	OpBitXor
+	OpShiftLeft
+	OpShiftRight
+	OpEqual
+	OpNotEqual
+	OpLess
+	OpGreater
+	OpLessEqual
+	OpGreaterEqual
+	OpInstanceOf
+
+	OpMinus
+	OpNot
+	OpBitNot
+
+	OpJump
+	OpJumpNotTruthy // pops the condition
+	OpJumpTruthy    // pops the condition
+
+	// Globals are the top level variables of a module
+	OpGetGlobal
+	OpSetGlobal    // assigns a declared global
+	OpDefineGlobal // declares a global
+
+	// Locals live on the stack frame of a function
+	OpGetLocal
+	OpSetLocal
+
+	// Cells hold the locals captured by closures
+	OpNewCell
+	OpGetCell
+	OpSetCell
+
+	// Free variables are the cells captured by the running closure
+	OpGetFree
+	OpSetFree
+
+	OpClosure
+	OpCall // calls the function below its arguments
+	OpPipe // calls the function on top with the value below it
+	OpReturnValue
+	OpReturn // returns nothing
+
+	OpArray
+	OpHash
+	OpIndex
+	OpSetIndex  // pops the value, the index and the indexed object
+	OpMember    // reads the field named by a constant
+	OpSetMember // pops the value and the object, assigns the field named by a constant
+	OpInstance  // creates an instance from a struct below pairs of field names and values
+	OpInterpolate
+
+	// OpClass creates a class named by a constant from pairs of method names
+	// and closures, above the superclass if it has one. OpSuper binds the
+	// method named by a constant of the superclass below self.
+	OpClass
+	OpSuper
+
+	// OpIterator stores an iterator over the popped value in a local,
+	// OpIterNext pushes its next one or two values or jumps once it is done
+	OpIterator
+	OpIterNext
+
+	// OpMatchArray pushes whether the popped value is an array of the given
+	// length, OpNoMatch raises the error of a match expression no arm of
+	// which matches the popped value
+	OpMatchArray
+	OpNoMatch
+
+	// OpUnpackArray replaces an array with its elements, the first one on
+	// top, OpUnpackField replaces a hash or an instance with the value of the
+	// key or field named by a constant
+	OpUnpackArray
+	OpUnpackField
+
+	// OpTry registers the handler jumped to when an error is raised, until
+	// the matching OpEndTry
+	OpTry
+	OpEndTry
+	OpThrow
+
+	OpImport     // pushes the module imported from a path by a file
+	OpImportName // replaces a module with one of its exports
+)
+
+// Definition describes an opcode for decoding and disassembly.
+type Definition struct {
+	Name          string
+	OperandWidths []int
+}
+
+var definitions = map[Opcode]*Definition{
+	OpConstant: {"OpConstant", []int{2}},
+	OpNull:     {"OpNull", []int{}},
+	OpTrue:     {"OpTrue", []int{}},
+	OpFalse:    {"OpFalse", []int{}},
+
+	OpPop:  {"OpPop", []int{}},
+	OpDup:  {"OpDup", []int{}},
+	OpDup2: {"OpDup2", []int{}},
+
+	OpAdd:          {"OpAdd", []int{}},
+	OpSub:          {"OpSub", []int{}},
+	OpMul:          {"OpMul", []int{}},
+	OpDiv:          {"OpDiv", []int{}},
+	OpMod:          {"OpMod", []int{}},
+	OpFloorDiv:     {"OpFloorDiv", []int{}},
+	OpPow:          {"OpPow", []int{}},
+	OpBitAnd:       {"OpBitAnd", []int{}},
+	OpBitOr:        {"OpBitOr", []int{}},
+	OpBitXor:       {"OpBitXor", []int{}},
+	OpShiftLeft:    {"OpShiftLeft", []int{}},
+	OpShiftRight:   {"OpShiftRight", []int{}},
+	OpEqual:        {"OpEqual", []int{}},
+	OpNotEqual:     {"OpNotEqual", []int{}},
+	OpLess:         {"OpLess", []int{}},
+	OpGreater:      {"OpGreater", []int{}},
+	OpLessEqual:    {"OpLessEqual", []int{}},
+	OpGreaterEqual: {"OpGreaterEqual", []int{}},
+	OpInstanceOf:   {"OpInstanceOf", []int{}},
+
+	OpMinus:  {"OpMinus", []int{}},
+	OpNot:    {"OpNot", []int{}},
+	OpBitNot: {"OpBitNot", []int{}},
+
+	OpJump:          {"OpJump", []int{2}},
+	OpJumpNotTruthy: {"OpJumpNotTruthy", []int{2}},
+	OpJumpTruthy:    {"OpJumpTruthy", []int{2}},
+
+	OpGetGlobal:    {"OpGetGlobal", []int{2}},
+	OpSetGlobal:    {"OpSetGlobal", []int{2}},
+	OpDefineGlobal: {"OpDefineGlobal", []int{2}},
+
+	OpGetLocal: {"OpGetLocal", []int{2}},
+	OpSetLocal: {"OpSetLocal", []int{2}},
+
+	OpNewCell: {"OpNewCell", []int{2}},
+	OpGetCell: {"OpGetCell", []int{2}},
+	OpSetCell: {"OpSetCell", []int{2}},
+
+	OpGetFree: {"OpGetFree", []int{2}},
+	OpSetFree: {"OpSetFree", []int{2}},
+
+	OpClosure:     {"OpClosure", []int{2}},
+	OpCall:        {"OpCall", []int{1}},
+	OpPipe:        {"OpPipe", []int{}},
+	OpReturnValue: {"OpReturnValue", []int{}},
+	OpReturn:      {"OpReturn", []int{}},
+
+	OpArray:       {"OpArray", []int{2}},
+	OpHash:        {"OpHash", []int{2}},
+	OpIndex:       {"OpIndex", []int{}},
+	OpSetIndex:    {"OpSetIndex", []int{}},
+	OpMember:      {"OpMember", []int{2}},
+	OpSetMember:   {"OpSetMember", []int{2}},
+	OpInstance:    {"OpInstance", []int{2}},
+	OpInterpolate: {"OpInterpolate", []int{2}},
+
+	// name, number of methods, whether there is a superclass
+	OpClass: {"OpClass", []int{2, 1, 1}},
+	OpSuper: {"OpSuper", []int{2}},
+
+	// local, number of loop variables, jump target
+	OpIterator: {"OpIterator", []int{2}},
+	OpIterNext: {"OpIterNext", []int{2, 1, 2}},
+
+	// number of elements, whether the remaining ones are bound too
+	OpMatchArray:  {"OpMatchArray", []int{2, 1}},
+	OpNoMatch:     {"OpNoMatch", []int{}},
+	OpUnpackArray: {"OpUnpackArray", []int{2, 1}},
+	OpUnpackField: {"OpUnpackField", []int{2}},
+
+	OpTry:    {"OpTry", []int{2}},
+	OpEndTry: {"OpEndTry", []int{}},
+	OpThrow:  {"OpThrow", []int{}},
+
+	// constants holding the imported path and the importing file
+	OpImport:     {"OpImport", []int{2, 2}},
+	OpImportName: {"OpImportName", []int{2}},
+}
+
+func Lookup(op Opcode) (*Definition, bool) {
+	def, ok := definitions[op]
+	return def, ok
+}
@@ -0,0 +1,136 @@
+package compiler
+
+import (
+	"strings"
+	"sunbird/internal/ast"
+	"sunbird/internal/code"
+	"sunbird/internal/object"
+)
+
+func (c *Compiler) compileVarStatement(node *ast.VarStatement) *object.Error {
+	if node.Pattern != nil {
+		if err := c.compile(node.Value); err != nil {
+			return err
+		}
+
+		return c.compileDestructure(node.Pattern, func(name string) (Symbol, *object.Error) {
+			return c.declareVar(node, name)
+		})
+	}
+
+	symbol, err := c.declareVar(node, node.Name.Value)
+	if err != nil {
+		return err
+	}
+
+	if err := c.compile(node.Value); err != nil {
+		return err
+	}
+
+	c.storeSymbol(symbol, true)
+
+	return nil
+}
+
+// declareVar declares a variable the way a var statement does, unless it was
+// hoisted already. Redeclared globals are only detected by the VM, since the
+// REPL may declare them on an earlier line.
+func (c *Compiler) declareVar(node ast.Node, name string) (Symbol, *object.Error) {
+	s := c.scope
+	if len(s.blocks) != 0 {
+		if _, ok := s.blocks[len(s.blocks)-1][name]; !ok && c.isLocal(name) {
+			return Symbol{}, c.errorAt(node, object.NameError, "Identifier '%s' has already been declared.", name)
+		}
+	}
+
+	symbol, isNew := c.declare(name)
+	if isNew && symbol.Scope == CellScope {
+		c.emit(code.OpNewCell, symbol.Index)
+	}
+
+	return symbol, nil
+}
+
+func (c *Compiler) compileAssignStatement(node *ast.AssignStatement) *object.Error {
+	var op code.Opcode
+
+	if node.Operator != "=" {
+		var ok bool
+		if op, ok = infixOperators[strings.TrimSuffix(node.Operator, "=")]; !ok {
+			return c.errorAt(node, object.TypeError, "unknown operator: %s", node.Operator)
+		}
+	}
+
+	switch target := node.Target.(type) {
+	case *ast.Identifier:
+		symbol, ok := c.resolve(target.Value)
+		if !ok {
+			symbol = c.symbols.Reference(target.Value)
+		}
+
+		if node.Operator != "=" {
+			c.loadSymbol(symbol)
+		}
+
+		if err := c.compile(node.Value); err != nil {
+			return err
+		}
+
+		if node.Operator != "=" {
+			c.emit(op)
+		}
+
+		c.storeSymbol(symbol, false)
+
+	case *ast.IndexExpression:
+		if err := c.compile(target.Left); err != nil {
+			return err
+		}
+
+		if err := c.compile(target.Index); err != nil {
+			return err
+		}
+
+		if node.Operator != "=" {
+			c.emit(code.OpDup2)
+			c.emit(code.OpIndex)
+		}
+
+		if err := c.compile(node.Value); err != nil {
+			return err
+		}
+
+		if node.Operator != "=" {
+			c.emit(op)
+		}
+
+		c.emit(code.OpSetIndex)
+
+	case *ast.MemberExpression:
+		if err := c.compile(target.Object); err != nil {
+			return err
+		}
+
+		name := c.addConstant(&object.String{Value: target.Property.Value})
+
+		if node.Operator != "=" {
+			c.emit(code.OpDup)
+			c.emit(code.OpMember, name)
+		}
+
+		if err := c.compile(node.Value); err != nil {
+			return err
+		}
+
+		if node.Operator != "=" {
+			c.emit(op)
+		}
+
+		c.emit(code.OpSetMember, name)
+
+	default:
+		return c.errorAt(node, object.TypeError, "cannot assign to %s", node.Target.String())
+	}
+
+	return nil
+}
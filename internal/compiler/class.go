@@ -0,0 +1,48 @@
+package compiler
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/code"
+	"sunbird/internal/object"
+)
+
+// compileClassDefinition compiles the superclass and the methods of a class
+// for OpClass. Like in the evaluator, the methods are closures of a block
+// holding the superclass as super.
+func (c *Compiler) compileClassDefinition(node *ast.ClassDefinition) *object.Error {
+	c.enterBlock()
+	defer c.leaveBlock()
+
+	hasSuperclass := 0
+
+	if node.Superclass != nil {
+		hasSuperclass = 1
+
+		if err := c.compile(node.Superclass); err != nil {
+			return err
+		}
+
+		c.emit(code.OpDup)
+
+		symbol, _ := c.declare("super")
+		if symbol.Scope == CellScope {
+			c.emit(code.OpNewCell, symbol.Index)
+		}
+
+		c.storeSymbol(symbol, true)
+	}
+
+	for _, method := range node.Methods {
+		c.emit(code.OpConstant, c.addConstant(&object.String{Value: method.TokenLiteral()}))
+
+		self := &ast.Identifier{Token: method.Token, Value: "self"}
+		if err := c.compileFunction(method, append([]ast.Pattern{self}, method.Parameters...)); err != nil {
+			return err
+		}
+	}
+
+	name := c.addConstant(&object.String{Value: node.Name.Value})
+	c.emit(code.OpClass, name, len(node.Methods), hasSuperclass)
+
+	return nil
+}
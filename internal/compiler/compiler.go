@@ -0,0 +1,615 @@
+package compiler
+
+import (
+	"fmt"
+	"sunbird/internal/ast"
+	"sunbird/internal/code"
+	"sunbird/internal/evaluator"
+	"sunbird/internal/object"
+)
+
+// Compiler lowers a program to bytecode for the VM.
+type Compiler struct {
+	symbols   *SymbolTable
+	constants []object.Object
+	builtins  map[string]int // constant index of each builtin used
+	exports   map[string]int // global index of each exported variable
+
+	scope   *scope
+	current ast.Node // the node instructions are emitted for
+	main    *object.CompiledFunction
+
+	// tooLarge is the first operand that did not fit in its instruction,
+	// reported once the program is compiled
+	tooLarge *object.Error
+}
+
+// Bytecode is a compiled program.
+type Bytecode struct {
+	Main      *object.CompiledFunction
+	Constants []object.Object
+	Exports   map[string]int
+}
+
+func New() *Compiler {
+	return NewWithState(NewSymbolTable(), []object.Object{})
+}
+
+// NewWithState creates a compiler that keeps compiling into the globals and
+// constants of an earlier compilation, as the REPL does for every line.
+func NewWithState(symbols *SymbolTable, constants []object.Object) *Compiler {
+	return &Compiler{
+		symbols:   symbols,
+		constants: constants,
+		builtins:  map[string]int{},
+		exports:   map[string]int{},
+	}
+}
+
+// Compile compiles a whole program. It reports the errors the evaluator
+// would only find when running the program, like declaring a variable
+// twice.
+func (c *Compiler) Compile(program *ast.Program) *object.Error {
+	c.scope = newScope(nil, program)
+	c.current = program
+	c.tooLarge = nil
+
+	if err := c.compileBody(program.Statements); err != nil {
+		return err
+	}
+
+	if c.tooLarge != nil {
+		return c.tooLarge
+	}
+
+	c.main = c.leaveScope(nil, nil)
+
+	return nil
+}
+
+func (c *Compiler) Bytecode() *Bytecode {
+	return &Bytecode{Main: c.main, Constants: c.constants, Exports: c.exports}
+}
+
+func (c *Compiler) compile(node ast.Node) *object.Error {
+	previous := c.current
+	c.current = node
+	defer func() { c.current = previous }()
+
+	switch node := node.(type) {
+	case *ast.ExpressionStatement:
+		if err := c.compile(node.Expression); err != nil {
+			return err
+		}
+
+		c.emit(code.OpPop)
+
+	case *ast.StringLiteral:
+		c.emit(code.OpConstant, c.addConstant(&object.String{Value: node.Value}))
+
+	case *ast.InterpolatedString:
+		for _, part := range node.Parts {
+			if err := c.compile(part); err != nil {
+				return err
+			}
+		}
+
+		c.emit(code.OpInterpolate, len(node.Parts))
+
+	case *ast.IntegerLiteral:
+		c.emit(code.OpConstant, c.addConstant(&object.Integer{Value: node.Value}))
+
+	case *ast.FloatLiteral:
+		c.emit(code.OpConstant, c.addConstant(&object.Float{Value: node.Value}))
+
+	case *ast.Boolean:
+		if node.Value {
+			c.emit(code.OpTrue)
+		} else {
+			c.emit(code.OpFalse)
+		}
+
+	case *ast.NullLiteral:
+		c.emit(code.OpNull)
+
+	case *ast.InfixExpression:
+		return c.compileInfixExpression(node)
+
+	case *ast.MatchExpression:
+		return c.compileMatchExpression(node)
+
+	case *ast.PrefixExpression:
+		if err := c.compile(node.Right); err != nil {
+			return err
+		}
+
+		switch node.Operator {
+		case "-":
+			c.emit(code.OpMinus)
+		case "!":
+			c.emit(code.OpNot)
+		case "~":
+			c.emit(code.OpBitNot)
+		default:
+			return c.errorAt(node, object.TypeError, "unknown operator: %s", node.Operator)
+		}
+
+	case *ast.IfExpression:
+		return c.compileIfExpression(node)
+
+	case *ast.WhileStatement:
+		return c.compileWhileStatement(node, false)
+
+	case *ast.ForStatement:
+		return c.compileForStatement(node, false)
+
+	case *ast.ForInStatement:
+		return c.compileForInStatement(node, false)
+
+	case *ast.BreakStatement:
+		return c.compileBreakStatement(node)
+
+	case *ast.ContinueStatement:
+		return c.compileContinueStatement(node)
+
+	case *ast.ThrowStatement:
+		if err := c.compile(node.Value); err != nil {
+			return err
+		}
+
+		c.emit(code.OpThrow)
+
+	case *ast.TryStatement:
+		return c.compileTryStatement(node, false)
+
+	case *ast.ImportStatement:
+		return c.compileImportStatement(node)
+
+	case *ast.ExportStatement:
+		if err := c.compile(node.Statement); err != nil {
+			return err
+		}
+
+		for _, name := range node.Statement.Names() {
+			symbol, _ := c.symbols.Resolve(name.Value)
+			c.exports[symbol.Name] = symbol.Index
+		}
+
+	case *ast.ReturnStatement:
+		return c.compileReturnStatement(node)
+
+	case *ast.VarStatement:
+		return c.compileVarStatement(node)
+
+	case *ast.AssignStatement:
+		return c.compileAssignStatement(node)
+
+	case *ast.Identifier:
+		symbol, ok := c.resolve(node.Value)
+		if !ok {
+			if builtin, ok := evaluator.LookupBuiltin(node.Value); ok {
+				c.emit(code.OpConstant, c.builtinConstant(node.Value, builtin))
+				return nil
+			}
+
+			symbol = c.symbols.Reference(node.Value)
+		}
+
+		c.loadSymbol(symbol)
+
+	case *ast.FunctionLiteral:
+		return c.compileFunctionLiteral(node)
+
+	case *ast.CallExpression:
+		if err := c.compile(node.Function); err != nil {
+			return err
+		}
+
+		if len(node.Arguments) > 255 {
+			return c.errorAt(node, object.TypeError, "too many arguments: %d", len(node.Arguments))
+		}
+
+		for _, arg := range node.Arguments {
+			if err := c.compile(arg); err != nil {
+				return err
+			}
+		}
+
+		c.emit(code.OpCall, len(node.Arguments))
+
+	case *ast.ArrayLiteral:
+		for _, element := range node.Elements {
+			if err := c.compile(element); err != nil {
+				return err
+			}
+		}
+
+		c.emit(code.OpArray, len(node.Elements))
+
+	case *ast.HashLiteral:
+		for _, pair := range node.Pairs {
+			if err := c.compile(pair.Key); err != nil {
+				return err
+			}
+
+			if err := c.compile(pair.Value); err != nil {
+				return err
+			}
+		}
+
+		c.emit(code.OpHash, len(node.Pairs))
+
+	case *ast.IndexExpression:
+		if err := c.compile(node.Left); err != nil {
+			return err
+		}
+
+		if err := c.compile(node.Index); err != nil {
+			return err
+		}
+
+		c.emit(code.OpIndex)
+
+	case *ast.MemberExpression:
+		if err := c.compile(node.Object); err != nil {
+			return err
+		}
+
+		c.emit(code.OpMember, c.addConstant(&object.String{Value: node.Property.Value}))
+
+	case *ast.StructDefinition:
+		fields := make([]string, len(node.Fields))
+		for i, field := range node.Fields {
+			fields[i] = field.Value
+		}
+
+		c.emit(code.OpConstant, c.addConstant(object.NewStructType(node.Name.Value, fields)))
+
+	case *ast.StructLiteral:
+		if err := c.compile(node.Struct); err != nil {
+			return err
+		}
+
+		for i, field := range node.Fields {
+			c.emit(code.OpConstant, c.addConstant(&object.String{Value: field.Value}))
+
+			if err := c.compile(node.Values[i]); err != nil {
+				return err
+			}
+		}
+
+		c.emit(code.OpInstance, len(node.Fields))
+
+	case *ast.ClassDefinition:
+		return c.compileClassDefinition(node)
+
+	case *ast.SuperExpression:
+		if err := c.compile(node.Class); err != nil {
+			return err
+		}
+
+		if err := c.compile(node.Self); err != nil {
+			return err
+		}
+
+		c.emit(code.OpSuper, c.addConstant(&object.String{Value: node.Method.Value}))
+
+	case *ast.BadStatement, *ast.BadExpression:
+		return c.errorAt(node, object.GenericError, "cannot evaluate code containing syntax errors")
+
+	default:
+		return c.errorAt(node, object.GenericError, "cannot compile %T", node)
+	}
+
+	return nil
+}
+
+// compileBody compiles the statements of a function or program, returning
+// the value of the last one like the evaluator does. A program ending with a
+// statement that has no value, like a declaration, returns nothing, while a
+// function returns null.
+func (c *Compiler) compileBody(stmts []ast.Statement) *object.Error {
+	if c.scope.outer == nil && (len(stmts) == 0 || !hasValue(stmts[len(stmts)-1])) {
+		if err := c.compileStatements(stmts); err != nil {
+			return err
+		}
+
+		c.emit(code.OpReturn)
+
+		return nil
+	}
+
+	if err := c.compileStatementsValue(stmts); err != nil {
+		return err
+	}
+
+	c.emit(code.OpReturnValue)
+
+	return nil
+}
+
+// compileBlockValue compiles a block used as an expression, like the branch
+// of an if, leaving the value of its last statement on the stack.
+func (c *Compiler) compileBlockValue(block *ast.BlockStatement) *object.Error {
+	return c.compileStatementsValue(block.Statements)
+}
+
+// compileStatementsValue compiles statements leaving the value of the last
+// one on the stack, or null when there are none.
+func (c *Compiler) compileStatementsValue(stmts []ast.Statement) *object.Error {
+	if len(stmts) == 0 {
+		c.emit(code.OpNull)
+		return nil
+	}
+
+	if err := c.compileStatements(stmts[:len(stmts)-1]); err != nil {
+		return err
+	}
+
+	return c.compileStatementValue(stmts[len(stmts)-1])
+}
+
+// compileStatementValue compiles a statement leaving its value on the stack.
+// Loops have the value of the last run of their body and try statements the
+// value of the block that ran last, other statements are null.
+func (c *Compiler) compileStatementValue(stmt ast.Statement) *object.Error {
+	previous := c.current
+	c.current = stmt
+	defer func() { c.current = previous }()
+
+	switch stmt := stmt.(type) {
+	case *ast.ExpressionStatement:
+		return c.compile(stmt.Expression)
+
+	case *ast.WhileStatement:
+		return c.compileWhileStatement(stmt, true)
+
+	case *ast.ForStatement:
+		return c.compileForStatement(stmt, true)
+
+	case *ast.ForInStatement:
+		return c.compileForInStatement(stmt, true)
+
+	case *ast.TryStatement:
+		return c.compileTryStatement(stmt, true)
+	}
+
+	if err := c.compile(stmt); err != nil {
+		return err
+	}
+
+	c.emit(code.OpNull)
+
+	return nil
+}
+
+// hasValue reports whether a statement has a value of its own.
+func hasValue(stmt ast.Statement) bool {
+	switch stmt.(type) {
+	case *ast.ExpressionStatement, *ast.WhileStatement, *ast.ForStatement,
+		*ast.ForInStatement, *ast.TryStatement:
+		return true
+	}
+
+	return false
+}
+
+func (c *Compiler) compileStatements(stmts []ast.Statement) *object.Error {
+	for _, stmt := range stmts {
+		if err := c.compile(stmt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var infixOperators = map[string]code.Opcode{
+	"+":          code.OpAdd,
+	"-":          code.OpSub,
+	"*":          code.OpMul,
+	"/":          code.OpDiv,
+	"%":          code.OpMod,
+	"~/":         code.OpFloorDiv,
+	"**":         code.OpPow,
+	"&":          code.OpBitAnd,
+	"|":          code.OpBitOr,
+	"^":          code.OpBitXor,
+	"<<":         code.OpShiftLeft,
+	">>":         code.OpShiftRight,
+	"==":         code.OpEqual,
+	"!=":         code.OpNotEqual,
+	"<":          code.OpLess,
+	">":          code.OpGreater,
+	"<=":         code.OpLessEqual,
+	">=":         code.OpGreaterEqual,
+	"instanceof": code.OpInstanceOf,
+	"|>":         code.OpPipe,
+}
+
+func (c *Compiler) compileInfixExpression(node *ast.InfixExpression) *object.Error {
+	if err := c.compile(node.Left); err != nil {
+		return err
+	}
+
+	if node.Operator == "&&" || node.Operator == "||" {
+		// the left operand is the result when it decides the outcome
+		c.emit(code.OpDup)
+
+		jump := code.OpJumpNotTruthy
+		if node.Operator == "||" {
+			jump = code.OpJumpTruthy
+		}
+
+		end := c.emit(jump, 0)
+		c.emit(code.OpPop)
+
+		if err := c.compile(node.Right); err != nil {
+			return err
+		}
+
+		c.patchJump(end)
+
+		return nil
+	}
+
+	if err := c.compile(node.Right); err != nil {
+		return err
+	}
+
+	op, ok := infixOperators[node.Operator]
+	if !ok {
+		return c.errorAt(node, object.TypeError, "unknown operator: %s", node.Operator)
+	}
+
+	c.emit(op)
+
+	return nil
+}
+
+func (c *Compiler) compileIfExpression(node *ast.IfExpression) *object.Error {
+	if err := c.compile(node.Condition); err != nil {
+		return err
+	}
+
+	alternative := c.emit(code.OpJumpNotTruthy, 0)
+
+	if err := c.compileBlockValue(node.Consequence); err != nil {
+		return err
+	}
+
+	end := c.emit(code.OpJump, 0)
+	c.patchJump(alternative)
+
+	if node.Alternative != nil {
+		if err := c.compileBlockValue(node.Alternative); err != nil {
+			return err
+		}
+	} else {
+		c.emit(code.OpNull)
+	}
+
+	c.patchJump(end)
+
+	return nil
+}
+
+func (c *Compiler) loadSymbol(symbol Symbol) {
+	switch symbol.Scope {
+	case GlobalScope:
+		c.emit(code.OpGetGlobal, symbol.Index)
+	case LocalScope:
+		c.emit(code.OpGetLocal, symbol.Index)
+	case CellScope:
+		c.emit(code.OpGetCell, symbol.Index)
+	case FreeScope:
+		c.emit(code.OpGetFree, symbol.Index)
+	}
+}
+
+// storeSymbol pops the value on top of the stack into a variable. Globals
+// are either declared or assigned, other variables are simply overwritten.
+func (c *Compiler) storeSymbol(symbol Symbol, declare bool) {
+	switch symbol.Scope {
+	case GlobalScope:
+		if declare {
+			c.emit(code.OpDefineGlobal, symbol.Index)
+		} else {
+			c.emit(code.OpSetGlobal, symbol.Index)
+		}
+	case LocalScope:
+		c.emit(code.OpSetLocal, symbol.Index)
+	case CellScope:
+		c.emit(code.OpSetCell, symbol.Index)
+	case FreeScope:
+		c.emit(code.OpSetFree, symbol.Index)
+	}
+}
+
+func (c *Compiler) addConstant(obj object.Object) int {
+	c.constants = append(c.constants, obj)
+	return len(c.constants) - 1
+}
+
+func (c *Compiler) builtinConstant(name string, builtin *object.Builtin) int {
+	if index, ok := c.builtins[name]; ok {
+		return index
+	}
+
+	index := c.addConstant(builtin)
+	c.builtins[name] = index
+
+	return index
+}
+
+// emit appends an instruction to the current function and returns its
+// offset.
+func (c *Compiler) emit(op code.Opcode, operands ...int) int {
+	s := c.scope
+	offset := len(s.instructions)
+
+	if n := len(s.locations); n == 0 || s.locations[n-1].Node != c.current {
+		s.locations = append(s.locations, object.Location{Offset: offset, Node: c.current})
+	}
+
+	c.checkOperands(op, operands)
+	s.instructions = append(s.instructions, code.Make(op, operands...)...)
+
+	return offset
+}
+
+// changeOperands rewrites the operands of the instruction at offset.
+func (c *Compiler) changeOperands(offset int, operands ...int) {
+	op := code.Opcode(c.scope.instructions[offset])
+
+	c.checkOperands(op, operands)
+	copy(c.scope.instructions[offset:], code.Make(op, operands...))
+}
+
+// checkOperands records an error when an operand is too large for its
+// instruction, like the index of a constant or the target of a jump in a
+// very large program.
+func (c *Compiler) checkOperands(op code.Opcode, operands []int) {
+	i, ok := code.Fits(op, operands...)
+	if ok || c.tooLarge != nil {
+		return
+	}
+
+	def, _ := code.Lookup(op)
+	c.tooLarge = c.errorAt(c.current, object.GenericError,
+		"program too large for the VM: operand %d of %s is %d, the limit is %d",
+		i+1, def.Name, operands[i], 1<<(8*def.OperandWidths[i])-1)
+}
+
+// patchJump points the jump at offset to the next instruction.
+func (c *Compiler) patchJump(offset int) {
+	c.changeOperands(offset, len(c.scope.instructions))
+}
+
+// leaveScope finishes the function being compiled and returns to the
+// enclosing one.
+func (c *Compiler) leaveScope(params []ast.Pattern, body *ast.BlockStatement) *object.CompiledFunction {
+	s := c.scope
+	c.scope = s.outer
+
+	return &object.CompiledFunction{
+		Instructions:  s.instructions,
+		Constants:     c.constants,
+		NumLocals:     s.numLocals,
+		NumCells:      s.numCells,
+		NumParameters: len(params),
+		Captures:      s.captures,
+		Locations:     s.locations,
+		Parameters:    params,
+		Body:          body,
+	}
+}
+
+func (c *Compiler) errorAt(node ast.Node, kind, format string, a ...interface{}) *object.Error {
+	return &object.Error{
+		Message: fmt.Sprintf(format, a...),
+		Kind:    kind,
+		Pos:     node.Pos(),
+		End:     node.End(),
+	}
+}
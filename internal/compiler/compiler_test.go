@@ -0,0 +1,255 @@
+package compiler_test
+
+import (
+	"strconv"
+	"strings"
+	"sunbird/internal/ast"
+	"sunbird/internal/code"
+	"sunbird/internal/compiler"
+	"sunbird/internal/lexer"
+	"sunbird/internal/object"
+	"sunbird/internal/parser"
+	"testing"
+)
+
+func parse(input string) *ast.Program {
+	return parser.New(lexer.New("", input)).ParseProgram()
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []code.Instructions
+	}{
+		{
+			"1 + 2",
+			[]code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpAdd),
+				code.Make(code.OpReturnValue),
+			},
+		},
+		{
+			"var x = 1; x",
+			[]code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpDefineGlobal, 0),
+				code.Make(code.OpGetGlobal, 0),
+				code.Make(code.OpReturnValue),
+			},
+		},
+		{
+			"true && false",
+			[]code.Instructions{
+				code.Make(code.OpTrue),
+				code.Make(code.OpDup),
+				code.Make(code.OpJumpNotTruthy, 7),
+				code.Make(code.OpPop),
+				code.Make(code.OpFalse),
+				code.Make(code.OpReturnValue),
+			},
+		},
+		{
+			"if true { 1 }; 2",
+			[]code.Instructions{
+				code.Make(code.OpTrue),
+				code.Make(code.OpJumpNotTruthy, 10),
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpJump, 11),
+				code.Make(code.OpNull),
+				code.Make(code.OpPop),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpReturnValue),
+			},
+		},
+		{
+			"class A {}; class B extends A { f() { super.f() } }",
+			[]code.Instructions{
+				code.Make(code.OpClass, 0, 0, 0),
+				code.Make(code.OpDefineGlobal, 0),
+				code.Make(code.OpGetGlobal, 0),
+				code.Make(code.OpDup),
+				code.Make(code.OpNewCell, 0),
+				code.Make(code.OpSetCell, 0),
+				code.Make(code.OpConstant, 1),
+				code.Make(code.OpClosure, 3),
+				code.Make(code.OpClass, 4, 1, 1),
+				code.Make(code.OpDefineGlobal, 1),
+				code.Make(code.OpReturn),
+			},
+		},
+		{
+			`var [a, ...b] = [1]; var {c, d: _} = {}`,
+			[]code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpArray, 1),
+				code.Make(code.OpUnpackArray, 1, 1),
+				code.Make(code.OpDefineGlobal, 0),
+				code.Make(code.OpDefineGlobal, 1),
+				code.Make(code.OpHash, 0),
+				code.Make(code.OpDup),
+				code.Make(code.OpUnpackField, 1),
+				code.Make(code.OpDefineGlobal, 2),
+				code.Make(code.OpUnpackField, 2),
+				code.Make(code.OpPop),
+				code.Make(code.OpReturn),
+			},
+		},
+		{
+			"match 1 { [x] => x }",
+			[]code.Instructions{
+				code.Make(code.OpConstant, 0),
+				code.Make(code.OpSetLocal, 0),
+				code.Make(code.OpGetLocal, 0),
+				code.Make(code.OpMatchArray, 1, 0),
+				code.Make(code.OpJumpNotTruthy, 38),
+				code.Make(code.OpGetLocal, 0),
+				code.Make(code.OpUnpackArray, 1, 0),
+				code.Make(code.OpSetLocal, 1),
+				code.Make(code.OpGetLocal, 1),
+				code.Make(code.OpSetLocal, 2),
+				code.Make(code.OpGetLocal, 2),
+				code.Make(code.OpJump, 42),
+				code.Make(code.OpGetLocal, 0),
+				code.Make(code.OpNoMatch),
+				code.Make(code.OpReturnValue),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		c := compiler.New()
+		if err := c.Compile(parse(tt.input)); err != nil {
+			t.Fatalf("%s: compile error: %s", tt.input, err.Message)
+		}
+
+		var expected code.Instructions
+		for _, ins := range tt.expected {
+			expected = append(expected, ins...)
+		}
+
+		if got := c.Bytecode().Main.Instructions; got.String() != expected.String() {
+			t.Errorf("%s: wrong instructions.\nexpected=\n%s\ngot=\n%s", tt.input, expected, got)
+		}
+	}
+}
+
+func TestClosureCaptures(t *testing.T) {
+	c := compiler.New()
+	if err := c.Compile(parse("var f = func(a) { func() { a } }")); err != nil {
+		t.Fatalf("compile error: %s", err.Message)
+	}
+
+	constants := c.Bytecode().Constants
+	if len(constants) != 2 {
+		t.Fatalf("wrong number of constants. got=%d", len(constants))
+	}
+
+	inner := constants[0].(*object.CompiledFunction)
+	if len(inner.Captures) != 1 || inner.Captures[0].Free || inner.Captures[0].Index != 0 {
+		t.Errorf("wrong captures. got=%+v", inner.Captures)
+	}
+
+	outer := constants[1].(*object.CompiledFunction)
+	if outer.NumParameters != 1 || outer.NumLocals != 1 || outer.NumCells != 1 {
+		t.Errorf("wrong frame layout. got=%d parameters, %d locals, %d cells",
+			outer.NumParameters, outer.NumLocals, outer.NumCells)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"var f = func(x) { var x = 1 }", "Identifier 'x' has already been declared."},
+		{"var f = func() { var y = 1; var y = 2 }", "Identifier 'y' has already been declared."},
+		{"while true { var a = 1; var g = func() { var a = 2 } }", "Identifier 'a' has already been declared."},
+		{`import "./my-module"`, `module name "my-module" is not an identifier, use import "./my-module" as name`},
+	}
+
+	for _, tt := range tests {
+		err := compiler.New().Compile(parse(tt.input))
+		if err == nil {
+			t.Errorf("%s: no compile error", tt.input)
+			continue
+		}
+
+		if err.Message != tt.expected {
+			t.Errorf("%s: wrong error. expected=%q, got=%q", tt.input, tt.expected, err.Message)
+		}
+	}
+}
+
+// repeat joins n copies of the text f makes for each index.
+func repeat(n int, sep string, f func(i int) string) string {
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = f(i)
+	}
+
+	return strings.Join(parts, sep)
+}
+
+// name returns a distinct identifier for i, which cannot have digits and
+// must not be a keyword.
+func name(i int) string {
+	s := ""
+	for {
+		s = string(rune('a'+i%26)) + s
+		i /= 26
+
+		if i == 0 {
+			return "x" + s
+		}
+	}
+}
+
+func TestOperandLimits(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"[" + repeat(70000, ", ", strconv.Itoa) + "]",
+			"operand 1 of OpConstant is 65536, the limit is 65535",
+		},
+		{
+			"var x = 0\n" + repeat(7000, "\n", func(int) string { return "x = x + 1" }) + "\nvar i = 0\nwhile i < 3 { i = i + 1 }",
+			"the limit is 65535",
+		},
+		{
+			"if true {\n" + repeat(7000, "\n", func(int) string { return "1 + 2 + 3" }) + "\n}",
+			"operand 1 of OpJumpNotTruthy is 84006, the limit is 65535",
+		},
+		{
+			repeat(70000, "\n", func(i int) string { return "var " + name(i) + " = null" }),
+			"operand 1 of OpDefineGlobal is 65536, the limit is 65535",
+		},
+		{
+			"class C {\n" + repeat(256, "\n", func(i int) string { return name(i) + "() { 1 }" }) + "\n}",
+			"operand 2 of OpClass is 256, the limit is 255",
+		},
+		{
+			"var [" + repeat(70000, ", ", func(int) string { return "_" }) + "] = []",
+			"operand 1 of OpUnpackArray is 70000, the limit is 65535",
+		},
+		{
+			"match [] { [" + repeat(70000, ", ", func(int) string { return "_" }) + "] => 1, _ => 2 }",
+			"operand 1 of OpMatchArray is 70000, the limit is 65535",
+		},
+	}
+
+	for _, tt := range tests {
+		err := compiler.New().Compile(parse(tt.input))
+		if err == nil {
+			t.Errorf("%.40s: no compile error", tt.input)
+			continue
+		}
+
+		if !strings.HasPrefix(err.Message, "program too large for the VM: ") || !strings.Contains(err.Message, tt.expected) {
+			t.Errorf("%.40s: wrong error. expected=%q, got=%q", tt.input, tt.expected, err.Message)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package compiler
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/code"
+	"sunbird/internal/object"
+)
+
+// compileDestructure pops the value on top of the stack into the variables
+// of a declaration or parameter pattern, which declare declares.
+func (c *Compiler) compileDestructure(pattern ast.Pattern, declare func(name string) (Symbol, *object.Error)) *object.Error {
+	previous := c.current
+	c.current = pattern
+	defer func() { c.current = previous }()
+
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		symbol, err := declare(pattern.Value)
+		if err != nil {
+			return err
+		}
+
+		c.storeSymbol(symbol, true)
+
+	case *ast.WildcardPattern:
+		c.emit(code.OpPop)
+
+	case *ast.ArrayPattern:
+		c.emit(code.OpUnpackArray, len(pattern.Elements), hasRest(pattern))
+
+		for _, element := range pattern.Elements {
+			if err := c.compileDestructure(element, declare); err != nil {
+				return err
+			}
+		}
+
+		if pattern.Rest != nil {
+			return c.compileDestructure(pattern.Rest, declare)
+		}
+
+	case *ast.HashPattern:
+		if len(pattern.Keys) == 0 {
+			c.emit(code.OpPop)
+		}
+
+		for i, key := range pattern.Keys {
+			// every key but the last one unpacks a copy of the value
+			if i < len(pattern.Keys)-1 {
+				c.emit(code.OpDup)
+			}
+
+			c.current = key
+			c.emit(code.OpUnpackField, c.addConstant(&object.String{Value: key.Value}))
+
+			if err := c.compileDestructure(pattern.Values[i], declare); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return c.errorAt(pattern, object.TypeError, "unknown pattern: %s", pattern.String())
+	}
+
+	return nil
+}
+
+// declareLocal declares a variable of the innermost block that may shadow
+// those of enclosing ones, like a parameter.
+func (c *Compiler) declareLocal(name string) (Symbol, *object.Error) {
+	symbol, isNew := c.declare(name)
+	if isNew && symbol.Scope == CellScope {
+		c.emit(code.OpNewCell, symbol.Index)
+	}
+
+	return symbol, nil
+}
+
+// hasRest is the operand telling OpMatchArray and OpUnpackArray whether a
+// pattern binds the remaining elements of arrays.
+func hasRest(pattern *ast.ArrayPattern) int {
+	if pattern.Rest != nil {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,85 @@
+package compiler
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/code"
+	"sunbird/internal/object"
+)
+
+func (c *Compiler) compileFunctionLiteral(node *ast.FunctionLiteral) *object.Error {
+	return c.compileFunction(node, node.Parameters)
+}
+
+// compileFunction compiles a function taking params, which are those of node
+// with self before them for methods.
+func (c *Compiler) compileFunction(node *ast.FunctionLiteral, params []ast.Pattern) *object.Error {
+	c.scope = newScope(c.scope, node)
+	c.enterBlock()
+
+	// arguments are passed in the first locals, the captured ones are moved
+	// to cells and the destructured ones unpacked into their variables
+	block := c.scope.blocks[0]
+	locals := make([]int, len(params))
+
+	for i, param := range params {
+		locals[i] = c.newLocal()
+
+		if param, ok := param.(*ast.Identifier); ok {
+			block[param.Value] = Symbol{Name: param.Value, Scope: LocalScope, Index: locals[i]}
+		}
+	}
+
+	for _, param := range params {
+		param, ok := param.(*ast.Identifier)
+		if !ok || !c.scope.captured[param.Value] {
+			continue
+		}
+
+		local := block[param.Value]
+		delete(block, param.Value)
+
+		symbol, _ := c.declare(param.Value)
+		c.emit(code.OpNewCell, symbol.Index)
+		c.emit(code.OpGetLocal, local.Index)
+		c.emit(code.OpSetCell, symbol.Index)
+	}
+
+	for i, param := range params {
+		if _, ok := param.(*ast.Identifier); ok {
+			continue
+		}
+
+		c.emit(code.OpGetLocal, locals[i])
+
+		if err := c.compileDestructure(param, c.declareLocal); err != nil {
+			return err
+		}
+	}
+
+	if err := c.hoist(node.Body.Statements); err != nil {
+		return err
+	}
+
+	if err := c.compileBody(node.Body.Statements); err != nil {
+		return err
+	}
+
+	fn := c.leaveScope(params, node.Body)
+	c.emit(code.OpClosure, c.addConstant(fn))
+
+	return nil
+}
+
+func (c *Compiler) compileReturnStatement(node *ast.ReturnStatement) *object.Error {
+	if err := c.compile(node.ReturnValue); err != nil {
+		return err
+	}
+
+	if err := c.exitTries(0); err != nil {
+		return err
+	}
+
+	c.emit(code.OpReturnValue)
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package compiler
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/code"
+	"sunbird/internal/module"
+	"sunbird/internal/object"
+	"sunbird/internal/token"
+)
+
+func (c *Compiler) compileImportStatement(node *ast.ImportStatement) *object.Error {
+	path := c.addConstant(&object.String{Value: node.Path.Value})
+	importer := c.addConstant(&object.String{Value: node.Token.Pos.Filename})
+	c.emit(code.OpImport, path, importer)
+
+	if node.Names != nil {
+		for _, name := range node.Names {
+			c.emit(code.OpDup)
+			c.emit(code.OpImportName, c.addConstant(&object.String{Value: name.Value}))
+
+			if err := c.bindImport(name); err != nil {
+				return err
+			}
+		}
+
+		c.emit(code.OpPop)
+
+		return nil
+	}
+
+	name := node.Alias
+	if name == nil {
+		moduleName := module.Name(node.Path.Value)
+		if !token.IsIdentifier(moduleName) {
+			return c.errorAt(node, object.ImportError,
+				"module name %q is not an identifier, use import %q as name", moduleName, node.Path.Value)
+		}
+
+		name = &ast.Identifier{Token: node.Token, Value: moduleName}
+	}
+
+	return c.bindImport(name)
+}
+
+// bindImport declares the variable an imported value is stored in, like a
+// var statement does.
+func (c *Compiler) bindImport(name *ast.Identifier) *object.Error {
+	previous := c.current
+	c.current = name
+	defer func() { c.current = previous }()
+
+	symbol, err := c.declareVar(name, name.Value)
+	if err != nil {
+		return err
+	}
+
+	c.storeSymbol(symbol, true)
+
+	return nil
+}
@@ -0,0 +1,263 @@
+package compiler
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/code"
+	"sunbird/internal/object"
+)
+
+// loop collects the jumps of the break and continue statements of a loop,
+// patched once its start and end are known.
+type loop struct {
+	breaks    []int
+	continues []int
+	tries     int // the number of enclosing try blocks when the loop started
+}
+
+func (c *Compiler) enterLoop() *loop {
+	l := &loop{tries: len(c.scope.tries)}
+	c.scope.loops = append(c.scope.loops, l)
+
+	return l
+}
+
+// leaveLoop patches the jumps of the innermost loop.
+func (c *Compiler) leaveLoop(continueTarget int) {
+	s := c.scope
+	l := s.loops[len(s.loops)-1]
+	s.loops = s.loops[:len(s.loops)-1]
+
+	for _, offset := range l.continues {
+		c.changeOperands(offset, continueTarget)
+	}
+
+	for _, offset := range l.breaks {
+		c.patchJump(offset)
+	}
+}
+
+func (c *Compiler) compileWhileStatement(node *ast.WhileStatement, value bool) *object.Error {
+	result := c.loopResult(value)
+	start := len(c.scope.instructions)
+
+	if err := c.compile(node.Condition); err != nil {
+		return err
+	}
+
+	end := c.emit(code.OpJumpNotTruthy, 0)
+
+	c.enterLoop()
+
+	if err := c.compileLoopBody(node.Body, result); err != nil {
+		return err
+	}
+
+	c.emit(code.OpJump, start)
+	c.patchJump(end)
+	c.leaveLoop(start)
+	c.loadLoopResult(result)
+
+	return nil
+}
+
+func (c *Compiler) compileForStatement(node *ast.ForStatement, value bool) *object.Error {
+	c.enterBlock()
+	defer c.leaveBlock()
+
+	result := c.loopResult(value)
+
+	if node.Init != nil {
+		// like the evaluator, assigning to an unknown variable declares it
+		if assign, ok := node.Init.(*ast.AssignStatement); ok {
+			if ident, ok := assign.Target.(*ast.Identifier); ok {
+				if _, ok := c.resolve(ident.Value); !ok {
+					symbol, err := c.declareVar(ident, ident.Value)
+					if err != nil {
+						return err
+					}
+
+					c.emit(code.OpNull)
+					c.storeSymbol(symbol, true)
+				}
+			}
+		}
+
+		if err := c.compile(node.Init); err != nil {
+			return err
+		}
+	}
+
+	start := len(c.scope.instructions)
+	end := -1
+
+	if node.Condition != nil {
+		if err := c.compile(node.Condition); err != nil {
+			return err
+		}
+
+		end = c.emit(code.OpJumpNotTruthy, 0)
+	}
+
+	c.enterLoop()
+
+	if err := c.compileLoopBody(node.Body, result); err != nil {
+		return err
+	}
+
+	update := len(c.scope.instructions)
+
+	if node.Update != nil {
+		if err := c.compile(node.Update); err != nil {
+			return err
+		}
+	}
+
+	c.emit(code.OpJump, start)
+
+	if end != -1 {
+		c.patchJump(end)
+	}
+
+	c.leaveLoop(update)
+	c.loadLoopResult(result)
+
+	return nil
+}
+
+func (c *Compiler) compileForInStatement(node *ast.ForInStatement, value bool) *object.Error {
+	if err := c.compile(node.Iterable); err != nil {
+		return err
+	}
+
+	c.enterBlock()
+	defer c.leaveBlock()
+
+	result := c.loopResult(value)
+	iterator := c.newLocal()
+	c.emit(code.OpIterator, iterator)
+
+	vars := []*ast.Identifier{node.Item}
+	if node.Index != nil {
+		vars = append(vars, node.Index)
+	}
+
+	start := c.emit(code.OpIterNext, iterator, len(vars), 0)
+
+	c.enterLoop()
+	c.enterBlock()
+
+	// the item is pushed last, on top of the index
+	for _, ident := range vars {
+		symbol, _ := c.declare(ident.Value)
+		if symbol.Scope == CellScope {
+			c.emit(code.OpNewCell, symbol.Index)
+		}
+
+		c.storeSymbol(symbol, true)
+	}
+
+	if err := c.hoist(node.Body.Statements); err != nil {
+		return err
+	}
+
+	if err := c.compileLoopStatements(node.Body.Statements, result); err != nil {
+		return err
+	}
+
+	c.leaveBlock()
+	c.emit(code.OpJump, start)
+	c.changeOperands(start, iterator, len(vars), len(c.scope.instructions))
+	c.leaveLoop(start)
+	c.loadLoopResult(result)
+
+	return nil
+}
+
+// compileLoopBody compiles the body of a loop in a block scope of its own, so
+// every iteration gets fresh variables.
+func (c *Compiler) compileLoopBody(body *ast.BlockStatement, result int) *object.Error {
+	c.enterBlock()
+	defer c.leaveBlock()
+
+	if err := c.hoist(body.Statements); err != nil {
+		return err
+	}
+
+	return c.compileLoopStatements(body.Statements, result)
+}
+
+// compileLoopStatements compiles the statements of a loop body, storing
+// their value in the result local when the value of the loop is used.
+func (c *Compiler) compileLoopStatements(stmts []ast.Statement, result int) *object.Error {
+	if result == -1 {
+		return c.compileStatements(stmts)
+	}
+
+	if err := c.compileStatementsValue(stmts); err != nil {
+		return err
+	}
+
+	c.emit(code.OpSetLocal, result)
+
+	return nil
+}
+
+// loopResult reserves the local holding the value of a loop when it is used,
+// null until the body runs to its end, and returns -1 otherwise.
+func (c *Compiler) loopResult(value bool) int {
+	if !value {
+		return -1
+	}
+
+	result := c.newLocal()
+	c.emit(code.OpNull)
+	c.emit(code.OpSetLocal, result)
+
+	return result
+}
+
+// loadLoopResult pushes the value of a loop once it is done.
+func (c *Compiler) loadLoopResult(result int) {
+	if result != -1 {
+		c.emit(code.OpGetLocal, result)
+	}
+}
+
+func (c *Compiler) compileBreakStatement(node *ast.BreakStatement) *object.Error {
+	l, err := c.innermostLoop(node)
+	if err != nil {
+		return err
+	}
+
+	if err := c.exitTries(l.tries); err != nil {
+		return err
+	}
+
+	l.breaks = append(l.breaks, c.emit(code.OpJump, 0))
+
+	return nil
+}
+
+func (c *Compiler) compileContinueStatement(node *ast.ContinueStatement) *object.Error {
+	l, err := c.innermostLoop(node)
+	if err != nil {
+		return err
+	}
+
+	if err := c.exitTries(l.tries); err != nil {
+		return err
+	}
+
+	l.continues = append(l.continues, c.emit(code.OpJump, 0))
+
+	return nil
+}
+
+func (c *Compiler) innermostLoop(node ast.Node) (*loop, *object.Error) {
+	loops := c.scope.loops
+	if len(loops) == 0 {
+		return nil, c.errorAt(node, object.GenericError, "%s outside of a loop", node.TokenLiteral())
+	}
+
+	return loops[len(loops)-1], nil
+}
@@ -0,0 +1,141 @@
+package compiler
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/code"
+	"sunbird/internal/object"
+)
+
+// compileMatchExpression stores the subject in a local and tries the arms in
+// turn, each in its own block scope. An arm that does not match jumps to the
+// next one, and the last one to OpNoMatch.
+func (c *Compiler) compileMatchExpression(node *ast.MatchExpression) *object.Error {
+	if err := c.compile(node.Subject); err != nil {
+		return err
+	}
+
+	subject := c.newLocal()
+	c.emit(code.OpSetLocal, subject)
+
+	var ends []int
+
+	for _, arm := range node.Arms {
+		c.enterBlock()
+
+		var fails, matched []int
+
+		for i, pattern := range arm.Patterns {
+			for _, offset := range fails {
+				c.patchJump(offset)
+			}
+
+			fails = nil
+
+			if err := c.compilePattern(pattern, subject, &fails); err != nil {
+				return err
+			}
+
+			if i < len(arm.Patterns)-1 {
+				matched = append(matched, c.emit(code.OpJump, 0))
+			}
+		}
+
+		for _, offset := range matched {
+			c.patchJump(offset)
+		}
+
+		if arm.Guard != nil {
+			if err := c.compile(arm.Guard); err != nil {
+				return err
+			}
+
+			fails = append(fails, c.emit(code.OpJumpNotTruthy, 0))
+		}
+
+		if err := c.hoist(arm.Body.Statements); err != nil {
+			return err
+		}
+
+		if err := c.compileBlockValue(arm.Body); err != nil {
+			return err
+		}
+
+		ends = append(ends, c.emit(code.OpJump, 0))
+
+		for _, offset := range fails {
+			c.patchJump(offset)
+		}
+
+		c.leaveBlock()
+	}
+
+	c.emit(code.OpGetLocal, subject)
+	c.emit(code.OpNoMatch)
+
+	for _, offset := range ends {
+		c.patchJump(offset)
+	}
+
+	return nil
+}
+
+// compilePattern matches the value in the local slot against pattern,
+// binding its variables, and adds the jumps taken when it does not match to
+// fails.
+func (c *Compiler) compilePattern(pattern ast.Pattern, slot int, fails *[]int) *object.Error {
+	switch pattern := pattern.(type) {
+	case *ast.WildcardPattern:
+
+	case *ast.Identifier:
+		symbol, _ := c.declare(pattern.Value)
+		if symbol.Scope == CellScope {
+			c.emit(code.OpNewCell, symbol.Index)
+		}
+
+		c.emit(code.OpGetLocal, slot)
+		c.storeSymbol(symbol, true)
+
+	case *ast.LiteralPattern:
+		c.emit(code.OpGetLocal, slot)
+
+		if err := c.compile(pattern.Value); err != nil {
+			return err
+		}
+
+		c.emit(code.OpEqual)
+		*fails = append(*fails, c.emit(code.OpJumpNotTruthy, 0))
+
+	case *ast.ArrayPattern:
+		n := len(pattern.Elements)
+
+		c.emit(code.OpGetLocal, slot)
+		c.emit(code.OpMatchArray, n, hasRest(pattern))
+		*fails = append(*fails, c.emit(code.OpJumpNotTruthy, 0))
+
+		// the elements are all stored before matching them, so that failing
+		// to match one leaves nothing on the stack
+		c.emit(code.OpGetLocal, slot)
+		c.emit(code.OpUnpackArray, n, hasRest(pattern))
+
+		locals := make([]int, n+hasRest(pattern))
+		for i := range locals {
+			locals[i] = c.newLocal()
+			c.emit(code.OpSetLocal, locals[i])
+		}
+
+		for i, element := range pattern.Elements {
+			if err := c.compilePattern(element, locals[i], fails); err != nil {
+				return err
+			}
+		}
+
+		if pattern.Rest != nil {
+			return c.compilePattern(pattern.Rest, locals[n], fails)
+		}
+
+	default:
+		return c.errorAt(pattern, object.TypeError, "unknown pattern: %s", pattern.String())
+	}
+
+	return nil
+}
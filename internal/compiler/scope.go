@@ -0,0 +1,226 @@
+package compiler
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/code"
+	"sunbird/internal/object"
+)
+
+// scope is the function being compiled. The program itself is the outermost
+// one, whose top level variables are globals.
+type scope struct {
+	outer *scope
+
+	instructions code.Instructions
+	locations    []object.Location
+
+	// blocks holds the variables of the nested block scopes, innermost last.
+	// Loop bodies, catch blocks and function bodies are block scopes, other
+	// blocks declare their variables in the enclosing one.
+	blocks    []map[string]Symbol
+	numLocals int
+	numCells  int
+
+	captures []object.Capture
+	free     map[string]Symbol
+
+	// captured holds the names used by nested functions, the locals with
+	// those names are kept in cells
+	captured map[string]bool
+
+	loops []*loop
+	tries []*tryBlock
+}
+
+func newScope(outer *scope, body ast.Node) *scope {
+	return &scope{outer: outer, free: map[string]Symbol{}, captured: capturedNames(body)}
+}
+
+func (c *Compiler) enterBlock() {
+	c.scope.blocks = append(c.scope.blocks, map[string]Symbol{})
+}
+
+func (c *Compiler) leaveBlock() {
+	c.scope.blocks = c.scope.blocks[:len(c.scope.blocks)-1]
+}
+
+// newLocal reserves a local slot that no variable refers to.
+func (c *Compiler) newLocal() int {
+	c.scope.numLocals++
+	return c.scope.numLocals - 1
+}
+
+// declare binds name in the innermost block, or as a global outside of all
+// blocks of the program. The second result is false when name was already
+// declared in that block, like a hoisted variable.
+func (c *Compiler) declare(name string) (Symbol, bool) {
+	s := c.scope
+	if len(s.blocks) == 0 {
+		return c.symbols.Define(name), true
+	}
+
+	block := s.blocks[len(s.blocks)-1]
+	if symbol, ok := block[name]; ok {
+		return symbol, false
+	}
+
+	var symbol Symbol
+	if s.captured[name] {
+		symbol = Symbol{Name: name, Scope: CellScope, Index: s.numCells}
+		s.numCells++
+	} else {
+		symbol = Symbol{Name: name, Scope: LocalScope, Index: s.numLocals}
+		s.numLocals++
+	}
+
+	block[name] = symbol
+
+	return symbol, true
+}
+
+// resolve finds the variable name refers to. Variables of enclosing functions
+// become free variables of all functions in between.
+func (c *Compiler) resolve(name string) (Symbol, bool) {
+	return c.resolveIn(c.scope, name)
+}
+
+func (c *Compiler) resolveIn(s *scope, name string) (Symbol, bool) {
+	for i := len(s.blocks) - 1; i >= 0; i-- {
+		if symbol, ok := s.blocks[i][name]; ok {
+			return symbol, true
+		}
+	}
+
+	if symbol, ok := s.free[name]; ok {
+		return symbol, true
+	}
+
+	if s.outer == nil {
+		return c.symbols.Resolve(name)
+	}
+
+	symbol, ok := c.resolveIn(s.outer, name)
+	if !ok || symbol.Scope == GlobalScope {
+		return symbol, ok
+	}
+
+	if symbol.Scope == LocalScope {
+		// capturedNames includes every name used in nested functions
+		panic("compiler: local " + name + " captured without a cell")
+	}
+
+	free := Symbol{Name: name, Scope: FreeScope, Index: len(s.captures)}
+	s.captures = append(s.captures, object.Capture{Free: symbol.Scope == FreeScope, Index: symbol.Index})
+	s.free[name] = free
+
+	return free, true
+}
+
+// isLocal reports whether name is a variable of a block scope, in this
+// function or an enclosing one.
+func (c *Compiler) isLocal(name string) bool {
+	for s := c.scope; s != nil; s = s.outer {
+		for _, block := range s.blocks {
+			if _, ok := block[name]; ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// hoist declares the variables of a block scope when entering it, so that
+// closures can refer to variables declared after them.
+func (c *Compiler) hoist(stmts []ast.Statement) *object.Error {
+	seen := map[string]bool{}
+
+	for _, stmt := range stmts {
+		if stmt, ok := stmt.(*ast.VarStatement); ok {
+			for _, name := range stmt.Names() {
+				if seen[name.Value] {
+					return c.errorAt(stmt, object.NameError, "Identifier '%s' has already been declared.", name.Value)
+				}
+
+				seen[name.Value] = true
+			}
+		}
+	}
+
+	clear(seen)
+
+	for _, stmt := range hoisted(stmts, nil) {
+		for _, ident := range stmt.Names() {
+			name := ident.Value
+			if seen[name] {
+				continue // declared in both branches of an if
+			}
+
+			if c.isLocal(name) {
+				return c.errorAt(stmt, object.NameError, "Identifier '%s' has already been declared.", name)
+			}
+
+			seen[name] = true
+
+			if symbol, _ := c.declare(name); symbol.Scope == CellScope {
+				c.emit(code.OpNewCell, symbol.Index)
+			}
+		}
+	}
+
+	return nil
+}
+
+// hoisted returns the var statements declaring variables in the block scope
+// made of stmts, which includes those in if and try blocks.
+func hoisted(stmts []ast.Statement, vars []*ast.VarStatement) []*ast.VarStatement {
+	for _, stmt := range stmts {
+		switch stmt := stmt.(type) {
+		case *ast.VarStatement:
+			vars = append(vars, stmt)
+
+		case *ast.ExpressionStatement:
+			if ifExp, ok := stmt.Expression.(*ast.IfExpression); ok {
+				vars = hoisted(ifExp.Consequence.Statements, vars)
+
+				if ifExp.Alternative != nil {
+					vars = hoisted(ifExp.Alternative.Statements, vars)
+				}
+			}
+
+		case *ast.TryStatement:
+			vars = hoisted(stmt.Block.Statements, vars)
+
+			if stmt.Finally != nil {
+				vars = hoisted(stmt.Finally.Statements, vars)
+			}
+		}
+	}
+
+	return vars
+}
+
+// capturedNames returns the identifiers used inside the functions nested in
+// body. It ignores shadowing, so it may include more names than needed.
+func capturedNames(body ast.Node) map[string]bool {
+	names := map[string]bool{}
+
+	ast.Inspect(body, func(node ast.Node) bool {
+		fn, ok := node.(*ast.FunctionLiteral)
+		if !ok || fn == body {
+			return true
+		}
+
+		ast.Inspect(fn, func(node ast.Node) bool {
+			if ident, ok := node.(*ast.Identifier); ok {
+				names[ident.Value] = true
+			}
+
+			return true
+		})
+
+		return false
+	})
+
+	return names
+}
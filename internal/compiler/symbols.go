@@ -0,0 +1,86 @@
+package compiler
+
+type SymbolScope uint8
+
+const (
+	GlobalScope SymbolScope = iota
+	LocalScope
+	CellScope // a local captured by a closure
+	FreeScope // a variable of an enclosing function
+)
+
+// Symbol is a resolved variable: where it lives and at which index.
+type Symbol struct {
+	Name  string
+	Scope SymbolScope
+	Index int
+}
+
+// SymbolTable holds the globals of a module. The REPL keeps it between lines
+// so later lines see the globals of earlier ones.
+type SymbolTable struct {
+	store    map[string]Symbol
+	declared map[string]bool
+}
+
+func NewSymbolTable() *SymbolTable {
+	return &SymbolTable{store: map[string]Symbol{}, declared: map[string]bool{}}
+}
+
+// Define declares a global.
+func (s *SymbolTable) Define(name string) Symbol {
+	s.declared[name] = true
+	return s.Reference(name)
+}
+
+// Reference returns the slot of a global, which may only be declared later
+// on, like a function called by another before its var statement.
+func (s *SymbolTable) Reference(name string) Symbol {
+	if symbol, ok := s.store[name]; ok {
+		return symbol
+	}
+
+	symbol := Symbol{Name: name, Scope: GlobalScope, Index: len(s.store)}
+	s.store[name] = symbol
+
+	return symbol
+}
+
+// Resolve returns the global name if it was declared.
+func (s *SymbolTable) Resolve(name string) (Symbol, bool) {
+	if !s.declared[name] {
+		return Symbol{}, false
+	}
+
+	return s.store[name], true
+}
+
+// Declared returns a copy of the names of the declared globals.
+func (s *SymbolTable) Declared() map[string]bool {
+	declared := make(map[string]bool, len(s.declared))
+	for name := range s.declared {
+		declared[name] = true
+	}
+
+	return declared
+}
+
+// Forget undeclares the globals that are not in declared, which Declared
+// returned earlier, and returns them.
+func (s *SymbolTable) Forget(declared map[string]bool) []Symbol {
+	var forgotten []Symbol
+
+	for name := range s.declared {
+		if !declared[name] {
+			delete(s.declared, name)
+			forgotten = append(forgotten, s.store[name])
+		}
+	}
+
+	return forgotten
+}
+
+// Len returns the number of global slots.
+func (s *SymbolTable) Len() int {
+	return len(s.store)
+}
@@ -0,0 +1,145 @@
+package compiler
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/code"
+	"sunbird/internal/object"
+)
+
+// tryBlock is a try block, or a catch block followed by a finally block,
+// whose handler is active while it is compiled.
+type tryBlock struct {
+	finally *ast.BlockStatement
+}
+
+// compileTryStatement lays out a try statement as:
+//
+//	OpTry handler; <try block>; OpEndTry; OpJump normal
+//	handler: <catch block>; OpJump normal
+//	rethrow: <finally block>; OpThrow
+//	normal: <finally block>
+//
+// where the catch block is itself protected by the rethrow handler when
+// there is a finally block. When value is set, the try and catch blocks leave
+// their value on the stack, kept aside while the finally block runs.
+func (c *Compiler) compileTryStatement(node *ast.TryStatement, value bool) *object.Error {
+	s := c.scope
+
+	compileBlock := c.compileStatements
+	if value {
+		compileBlock = c.compileStatementsValue
+	}
+
+	handler := c.emit(code.OpTry, 0)
+	s.tries = append(s.tries, &tryBlock{finally: node.Finally})
+
+	if err := compileBlock(node.Block.Statements); err != nil {
+		return err
+	}
+
+	s.tries = s.tries[:len(s.tries)-1]
+	c.emit(code.OpEndTry)
+
+	normal := []int{c.emit(code.OpJump, 0)}
+
+	c.patchJump(handler)
+
+	if node.Catch != nil {
+		c.enterBlock()
+
+		// the handler starts with the caught error on the stack
+		symbol, _ := c.declare(node.CatchParam.Value)
+		if symbol.Scope == CellScope {
+			c.emit(code.OpNewCell, symbol.Index)
+		}
+
+		c.storeSymbol(symbol, true)
+
+		rethrow := -1
+		if node.Finally != nil {
+			rethrow = c.emit(code.OpTry, 0)
+			s.tries = append(s.tries, &tryBlock{finally: node.Finally})
+		}
+
+		if err := c.hoist(node.Catch.Statements); err != nil {
+			return err
+		}
+
+		if err := compileBlock(node.Catch.Statements); err != nil {
+			return err
+		}
+
+		if node.Finally != nil {
+			s.tries = s.tries[:len(s.tries)-1]
+			c.emit(code.OpEndTry)
+		}
+
+		c.leaveBlock()
+		normal = append(normal, c.emit(code.OpJump, 0))
+
+		if rethrow != -1 {
+			c.patchJump(rethrow)
+		}
+	}
+
+	if node.Finally != nil {
+		pending := c.newLocal()
+		c.emit(code.OpSetLocal, pending)
+
+		if err := c.compileStatements(node.Finally.Statements); err != nil {
+			return err
+		}
+
+		c.emit(code.OpGetLocal, pending)
+		c.emit(code.OpThrow)
+	}
+
+	for _, offset := range normal {
+		c.patchJump(offset)
+	}
+
+	if node.Finally == nil {
+		return nil
+	}
+
+	if !value {
+		return c.compileStatements(node.Finally.Statements)
+	}
+
+	result := c.newLocal()
+	c.emit(code.OpSetLocal, result)
+
+	if err := c.compileStatements(node.Finally.Statements); err != nil {
+		return err
+	}
+
+	c.emit(code.OpGetLocal, result)
+
+	return nil
+}
+
+// exitTries leaves the try blocks of the current function entered since
+// depth, innermost first, running their finally blocks, before a return,
+// break or continue jumps out of them.
+func (c *Compiler) exitTries(depth int) *object.Error {
+	s := c.scope
+	tries := s.tries
+
+	defer func() { s.tries = tries }()
+
+	for i := len(tries) - 1; i >= depth; i-- {
+		c.emit(code.OpEndTry)
+
+		if tries[i].finally == nil {
+			continue
+		}
+
+		s.tries = tries[:i]
+
+		if err := c.compileStatements(tries[i].finally.Statements); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
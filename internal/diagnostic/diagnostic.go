@@ -0,0 +1,124 @@
+package diagnostic
+
+import (
+	"fmt"
+	"sunbird/internal/token"
+)
+
+type Severity uint8
+
+const (
+	Error Severity = iota
+	Warning
+	Note
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Error:
+		return "error"
+	case Warning:
+		return "warning"
+	case Note:
+		return "note"
+	default:
+		return "unknown"
+	}
+}
+
+// Code identifies the kind of a diagnostic, so tools can match on it without
+// parsing the message.
+type Code string
+
+const (
+	// Lexer
+	IllegalCharacter    Code = "E0001"
+	UnterminatedString  Code = "E0002"
+	InvalidEscape       Code = "E0003"
+	UnterminatedComment Code = "E0004"
+
+	// Parser
+	UnexpectedToken    Code = "E0100"
+	MissingExpression  Code = "E0101"
+	InvalidNumber      Code = "E0102"
+	InvalidAssignment  Code = "E0103"
+	LoopControlOutside Code = "E0104"
+	MisplacedExport    Code = "E0105"
+	DuplicateMember    Code = "E0106"
+	InvalidPattern     Code = "E0107"
+
+	// Resolver
+	UndeclaredVariable    Code = "E0300"
+	UsedBeforeDeclaration Code = "E0301"
+	DuplicateDeclaration  Code = "E0302"
+	InvalidSuper          Code = "E0303"
+	InvalidModuleName     Code = "E0304"
+
+	// Evaluator
+	RuntimeError Code = "E0200"
+)
+
+// Span is the part of the source a diagnostic refers to. End is the position
+// immediately after the last character.
+type Span struct {
+	Start token.Position
+	End   token.Position
+}
+
+func (s Span) IsValid() bool {
+	return s.Start.IsValid()
+}
+
+type Diagnostic struct {
+	Severity Severity
+	Code     Code
+	Span     Span
+	Message  string
+	Notes    []string
+}
+
+func New(code Code, span Span, format string, a ...interface{}) Diagnostic {
+	return Diagnostic{
+		Severity: Error,
+		Code:     code,
+		Span:     span,
+		Message:  fmt.Sprintf(format, a...),
+	}
+}
+
+// WithNote returns a copy of the diagnostic with an extra note.
+func (d Diagnostic) WithNote(format string, a ...interface{}) Diagnostic {
+	d.Notes = append(append([]string{}, d.Notes...), fmt.Sprintf(format, a...))
+	return d
+}
+
+// Error formats the diagnostic on a single line, prefixed by its location if
+// it has one.
+func (d Diagnostic) Error() string {
+	msg := fmt.Sprintf("%s[%s]: %s", d.Severity, d.Code, d.Message)
+
+	if !d.Span.IsValid() {
+		return msg
+	}
+
+	return Location(d.Span.Start) + ": " + msg
+}
+
+// Location formats pos as file:line:col, leaving out an empty filename.
+func Location(pos token.Position) string {
+	if pos.Filename == "" {
+		return fmt.Sprintf("%d:%d", pos.Line, pos.Col)
+	}
+
+	return fmt.Sprintf("%s:%d:%d", pos.Filename, pos.Line, pos.Col)
+}
+
+// Messages returns the messages of the diagnostics.
+func Messages(diagnostics []Diagnostic) []string {
+	messages := make([]string, len(diagnostics))
+	for i, d := range diagnostics {
+		messages[i] = d.Message
+	}
+
+	return messages
+}
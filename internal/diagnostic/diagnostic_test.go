@@ -0,0 +1,127 @@
+package diagnostic_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"sunbird/internal/diagnostic"
+	"sunbird/internal/lexer"
+	"sunbird/internal/parser"
+	"testing"
+)
+
+func parse(filename, input string) []diagnostic.Diagnostic {
+	p := parser.New(lexer.New(filename, input))
+	p.ParseProgram()
+
+	return p.Diagnostics()
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			"var x = 1;\nvar y = foo(1, 2;",
+			`error[E0100]: expected next token to be ), got ; instead
+ --> main.sb:2:17
+  |
+2 | var y = foo(1, 2;
+  |                 ^
+`,
+		},
+		{
+			"\tvar s = \"a\\qb\";",
+			`error[E0003]: unknown escape sequence: \q
+ --> main.sb:1:12
+  |
+1 | 	var s = "a\qb";
+  | 	          ^^
+`,
+		},
+		{
+			"var s = \"never\nclosed",
+			`error[E0002]: unterminated string
+ --> main.sb:1:9
+  |
+1 | var s = "never
+  |         ^^^^^^
+`,
+		},
+		{
+			"break;",
+			`error[E0104]: break outside of loop
+ --> main.sb:1:1
+  |
+1 | break;
+  | ^^^^^
+  = note: break and continue can only be used inside for and while loops
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+
+		renderer := diagnostic.NewRenderer(&out, false)
+		renderer.AddFile("main.sb", tt.input)
+		renderer.Render(parse("main.sb", tt.input))
+
+		if out.String() != tt.expected {
+			t.Errorf("%q: wrong output.\nexpected:\n%s\ngot:\n%s", tt.input, tt.expected, out.String())
+		}
+	}
+}
+
+func TestRenderWithoutSpan(t *testing.T) {
+	var out bytes.Buffer
+
+	d := diagnostic.New(diagnostic.RuntimeError, diagnostic.Span{}, "division by zero")
+	diagnostic.NewRenderer(&out, false).Render([]diagnostic.Diagnostic{d})
+
+	expected := "error[E0200]: division by zero\n"
+	if out.String() != expected {
+		t.Errorf("wrong output. expected=%q, got=%q", expected, out.String())
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var out bytes.Buffer
+
+	if err := diagnostic.WriteJSON(&out, parse("main.sb", "x = ;")); err != nil {
+		t.Fatalf("WriteJSON failed: %s", err)
+	}
+
+	var decoded []struct {
+		Severity string
+		Code     string
+		Message  string
+		Notes    []string
+		Span     struct {
+			Start struct{ Filename, Line, Col interface{} }
+			End   struct{ Line, Col interface{} }
+		}
+	}
+
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %s\n%s", err, out.String())
+	}
+
+	if len(decoded) != 1 {
+		t.Fatalf("expected 1 diagnostic. got=%d", len(decoded))
+	}
+
+	d := decoded[0]
+	if d.Severity != "error" || d.Code != "E0101" || d.Message != "no prefix parse function for ; found" {
+		t.Errorf("wrong diagnostic. got=%+v", d)
+	}
+
+	if d.Span.Start.Filename != "main.sb" || d.Span.Start.Line != 1.0 || d.Span.Start.Col != 5.0 ||
+		d.Span.End.Col != 6.0 {
+		t.Errorf("wrong span. got=%+v", d.Span)
+	}
+
+	if len(d.Notes) != 1 {
+		t.Errorf("expected 1 note. got=%q", d.Notes)
+	}
+}
@@ -0,0 +1,59 @@
+package diagnostic
+
+import (
+	"encoding/json"
+	"io"
+	"sunbird/internal/token"
+)
+
+type jsonPosition struct {
+	Filename string `json:"filename"`
+	Offset   int    `json:"offset"`
+	Line     int    `json:"line"`
+	Col      int    `json:"col"`
+}
+
+type jsonSpan struct {
+	Start jsonPosition `json:"start"`
+	End   jsonPosition `json:"end"`
+}
+
+type jsonDiagnostic struct {
+	Severity string    `json:"severity"`
+	Code     string    `json:"code"`
+	Span     *jsonSpan `json:"span"` // null when the diagnostic has no location
+	Message  string    `json:"message"`
+	Notes    []string  `json:"notes"`
+}
+
+// WriteJSON writes the diagnostics as a JSON array, for editors and other
+// tools.
+func WriteJSON(out io.Writer, diagnostics []Diagnostic) error {
+	list := make([]jsonDiagnostic, len(diagnostics))
+
+	for i, d := range diagnostics {
+		list[i] = jsonDiagnostic{
+			Severity: d.Severity.String(),
+			Code:     string(d.Code),
+			Message:  d.Message,
+			Notes:    d.Notes,
+		}
+
+		if list[i].Notes == nil {
+			list[i].Notes = []string{}
+		}
+
+		if d.Span.IsValid() {
+			list[i].Span = &jsonSpan{Start: toJSON(d.Span.Start), End: toJSON(d.Span.End)}
+		}
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(list)
+}
+
+func toJSON(pos token.Position) jsonPosition {
+	return jsonPosition{Filename: pos.Filename, Offset: pos.Offset, Line: pos.Line, Col: pos.Col}
+}
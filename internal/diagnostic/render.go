@@ -0,0 +1,162 @@
+package diagnostic
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sunbird/internal/token"
+	"unicode/utf8"
+)
+
+const (
+	reset  = "\x1b[0m"
+	bold   = "\x1b[1m"
+	red    = "\x1b[1;31m"
+	yellow = "\x1b[1;33m"
+	cyan   = "\x1b[1;36m"
+	blue   = "\x1b[1;34m"
+)
+
+// Renderer prints diagnostics for humans, showing the offending source line
+// with the span underlined by carets.
+type Renderer struct {
+	out   io.Writer
+	color bool
+	files map[string]string // source by filename
+}
+
+func NewRenderer(out io.Writer, color bool) *Renderer {
+	return &Renderer{out: out, color: color, files: map[string]string{}}
+}
+
+// AddFile makes the source of filename available for snippets. Input without
+// a filename, like REPL lines, is added with an empty filename.
+func (r *Renderer) AddFile(filename, src string) {
+	r.files[filename] = src
+}
+
+func (r *Renderer) Render(diagnostics []Diagnostic) {
+	for _, d := range diagnostics {
+		r.render(d)
+	}
+}
+
+func (r *Renderer) render(d Diagnostic) {
+	label := r.paint(severityColor(d.Severity), d.Severity.String()+"["+string(d.Code)+"]")
+	fmt.Fprintf(r.out, "%s%s\n", label, r.paint(bold, ": "+d.Message))
+
+	gutter := ""
+
+	if d.Span.IsValid() {
+		start := d.Span.Start
+		number := strconv.Itoa(start.Line)
+		gutter = strings.Repeat(" ", len(number))
+
+		fmt.Fprintf(r.out, "%s%s %s\n", gutter, r.paint(blue, "-->"), Location(start))
+
+		if line, ok := r.line(start); ok {
+			fmt.Fprintf(r.out, "%s\n", r.paint(blue, gutter+" |"))
+			fmt.Fprintf(r.out, "%s %s\n", r.paint(blue, number+" |"), line)
+			fmt.Fprintf(r.out, "%s %s\n", r.paint(blue, gutter+" |"),
+				r.paint(severityColor(d.Severity), underline(line, d.Span)))
+		}
+	}
+
+	for _, note := range d.Notes {
+		fmt.Fprintf(r.out, "%s %s %s\n", gutter, r.paint(blue, "="), r.paint(bold, "note:")+" "+note)
+	}
+}
+
+// line returns the source line containing pos, without its line ending.
+func (r *Renderer) line(pos token.Position) (string, bool) {
+	src, ok := r.files[pos.Filename]
+	if !ok || pos.Offset > len(src) {
+		return "", false
+	}
+
+	start := strings.LastIndexByte(src[:pos.Offset], '\n') + 1
+
+	end := strings.IndexByte(src[pos.Offset:], '\n')
+	if end < 0 {
+		end = len(src)
+	} else {
+		end += pos.Offset
+	}
+
+	return strings.TrimRight(src[start:end], "\r"), true
+}
+
+// underline returns the padding and carets placed under the span in line.
+// Spans covering several lines are underlined up to the end of the first one.
+func underline(line string, span Span) string {
+	from := span.Start.Col - 1
+	if from > len(line) {
+		from = len(line)
+	}
+
+	to := len(line)
+	if span.End.Line == span.Start.Line && span.End.Col-1 < to {
+		to = span.End.Col - 1
+	}
+
+	var out strings.Builder
+
+	// tabs are kept so the carets line up however the terminal renders them
+	for _, ch := range line[:from] {
+		if ch == '\t' {
+			out.WriteByte('\t')
+		} else {
+			out.WriteByte(' ')
+		}
+	}
+
+	carets := 1
+	if to > from {
+		carets = utf8.RuneCountInString(line[from:to])
+	}
+
+	out.WriteString(strings.Repeat("^", carets))
+
+	return out.String()
+}
+
+func (r *Renderer) paint(color, s string) string {
+	if !r.color {
+		return s
+	}
+
+	return color + s + reset
+}
+
+func severityColor(s Severity) string {
+	switch s {
+	case Warning:
+		return yellow
+	case Note:
+		return cyan
+	default:
+		return red
+	}
+}
+
+// UseColor reports whether colour should be used when writing to w, which is
+// the case for terminals unless the NO_COLOR environment variable is set.
+func UseColor(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,131 @@
+package evaluator
+
+import (
+	"strings"
+	"sunbird/internal/ast"
+	"sunbird/internal/object"
+)
+
+func evalAssignStatement(as *ast.AssignStatement, env *object.Environment) object.Object {
+	switch target := as.Target.(type) {
+	case *ast.Identifier:
+		return evalIdentifierAssignment(as, target, env)
+
+	case *ast.IndexExpression:
+		return evalIndexAssignment(as, target, env)
+
+	case *ast.MemberExpression:
+		return evalMemberAssignment(as, target, env)
+
+	default:
+		return newTypedError(object.TypeError, "cannot assign to %s", as.Target.String())
+	}
+}
+
+func evalIdentifierAssignment(
+	as *ast.AssignStatement,
+	target *ast.Identifier,
+	env *object.Environment,
+) object.Object {
+	current, ok := env.Get(target.Binding.Depth, target.Binding.Slot)
+	if !ok {
+		return newTypedError(object.NameError, "Identifier '%s' has not been declared.", target.Value)
+	}
+
+	val := evalAssignedValue(as, current, env)
+	if isError(val) {
+		return val
+	}
+
+	setVariable(env, target, val)
+
+	return nil
+}
+
+func evalIndexAssignment(
+	as *ast.AssignStatement,
+	target *ast.IndexExpression,
+	env *object.Environment,
+) object.Object {
+	left := Eval(target.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	index := Eval(target.Index, env)
+	if isError(index) {
+		return index
+	}
+
+	var current object.Object
+	if as.Operator != "=" {
+		current = evalIndexExpression(left, index)
+		if isError(current) {
+			return current
+		}
+	}
+
+	val := evalAssignedValue(as, current, env)
+	if isError(val) {
+		return val
+	}
+
+	return setIndex(left, index, val)
+}
+
+// setIndex stores val in the array or hash left, under index.
+func setIndex(left, index, val object.Object) object.Object {
+	switch left := left.(type) {
+	case *object.Array:
+		return assignArrayIndex(left, index, val)
+
+	case *object.Hash:
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return newTypedError(object.TypeError, "unusable as hash key: %s", index.Type().String())
+		}
+
+		left.Set(key, val)
+		return nil
+
+	default:
+		return newTypedError(object.TypeError, "index assignment not supported: %s", left.Type().String())
+	}
+}
+
+// evalAssignedValue evaluates the right side of an assignment, combining it
+// with the current value of the target for compound operators like +=.
+func evalAssignedValue(
+	as *ast.AssignStatement,
+	current object.Object,
+	env *object.Environment,
+) object.Object {
+	val := Eval(as.Value, env)
+	if isError(val) || as.Operator == "=" {
+		return val
+	}
+
+	operator := strings.TrimSuffix(as.Operator, "=")
+
+	return allocate(env.Runtime(), evalInfixExpression(operator, current, val))
+}
+
+func assignArrayIndex(array *object.Array, index, val object.Object) object.Object {
+	integer, ok := index.(*object.Integer)
+	if !ok {
+		return newTypedError(object.TypeError, "array index must be an integer, got %s", index.Type().String())
+	}
+
+	idx := integer.Value
+	if idx < 0 {
+		idx += int64(len(array.Elements))
+	}
+
+	if idx < 0 || idx >= int64(len(array.Elements)) {
+		return newTypedError(object.IndexError, "index out of range: %d", integer.Value)
+	}
+
+	array.Elements[idx] = val
+
+	return nil
+}
@@ -13,7 +13,8 @@ func evalBlockStatement(block *ast.BlockStatement, env *object.Environment) obje
 
 		if result != nil {
 			rt := result.Type()
-			if rt == object.ReturnValueObj || rt == object.ErrorObj {
+			if rt == object.ReturnValueObj || rt == object.ErrorObj ||
+				rt == object.BreakObj || rt == object.ContinueObj {
 				return result
 			}
 		}
@@ -7,9 +7,9 @@ import (
 
 var builtins = map[string]*object.Builtin{
 	"len": {
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(_ *object.Runtime, args ...object.Object) object.Object {
 			if len(args) != 1 {
-				return newError("wrong number of arguments. got=%d, want=1",
+				return newTypedError(object.TypeError, "wrong number of arguments. got=%d, want=1",
 					len(args))
 			}
 
@@ -20,45 +20,279 @@ var builtins = map[string]*object.Builtin{
 			case *object.Array:
 				return &object.Integer{Value: int64(len(arg.Elements))}
 
+			case *object.Hash:
+				return &object.Integer{Value: int64(arg.Len())}
+
+			case *object.Range:
+				return &object.Integer{Value: arg.Len()}
+
 			default:
-				return newError("argument to `len` not supported, got %s", args[0].Type().String())
+				return newTypedError(object.TypeError, "argument to `len` not supported, got %s", args[0].Type().String())
 			}
 		},
 	},
 	"append": {
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(_ *object.Runtime, args ...object.Object) object.Object {
 			arr, ok := args[0].(*object.Array)
 			if !ok {
-				return newError(
+				return newTypedError(
+					object.TypeError,
 					"first argument to `append` must be an array, got %s",
 					args[0].Type().String(),
 				)
 			}
 
-			newElements := append(arr.Elements, args[1:]...)
+			// Copy so that arrays returned by append never share elements with the
+			// original, which could otherwise be mutated through index assignment
+			newElements := make([]object.Object, 0, len(arr.Elements)+len(args)-1)
+			newElements = append(newElements, arr.Elements...)
+			newElements = append(newElements, args[1:]...)
 
 			return &object.Array{Elements: newElements}
 		},
 	},
 
+	"keys": {
+		Fn: func(_ *object.Runtime, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newTypedError(object.TypeError, "wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			hash, ok := args[0].(*object.Hash)
+			if !ok {
+				return newTypedError(object.TypeError, "argument to `keys` must be a hash, got %s", args[0].Type().String())
+			}
+
+			keys := []object.Object{}
+			for _, pair := range hash.Entries() {
+				keys = append(keys, pair.Key)
+			}
+
+			return &object.Array{Elements: keys}
+		},
+	},
+
+	"values": {
+		Fn: func(_ *object.Runtime, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newTypedError(object.TypeError, "wrong number of arguments. got=%d, want=1",
+					len(args))
+			}
+
+			hash, ok := args[0].(*object.Hash)
+			if !ok {
+				return newTypedError(
+					object.TypeError,
+					"argument to `values` must be a hash, got %s",
+					args[0].Type().String(),
+				)
+			}
+
+			values := []object.Object{}
+			for _, pair := range hash.Entries() {
+				values = append(values, pair.Value)
+			}
+
+			return &object.Array{Elements: values}
+		},
+	},
+
+	"has": {
+		Fn: func(_ *object.Runtime, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newTypedError(object.TypeError, "wrong number of arguments. got=%d, want=2",
+					len(args))
+			}
+
+			hash, ok := args[0].(*object.Hash)
+			if !ok {
+				return newTypedError(
+					object.TypeError,
+					"first argument to `has` must be a hash, got %s",
+					args[0].Type().String(),
+				)
+			}
+
+			key, ok := args[1].(object.Hashable)
+			if !ok {
+				return newTypedError(object.TypeError, "unusable as hash key: %s", args[1].Type().String())
+			}
+
+			_, found := hash.Get(key)
+
+			return nativeBoolToBooleanObject(found)
+		},
+	},
+
+	"delete": {
+		Fn: func(_ *object.Runtime, args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return newTypedError(object.TypeError, "wrong number of arguments. got=%d, want=2",
+					len(args))
+			}
+
+			hash, ok := args[0].(*object.Hash)
+			if !ok {
+				return newTypedError(
+					object.TypeError,
+					"first argument to `delete` must be a hash, got %s",
+					args[0].Type().String(),
+				)
+			}
+
+			key, ok := args[1].(object.Hashable)
+			if !ok {
+				return newTypedError(object.TypeError, "unusable as hash key: %s", args[1].Type().String())
+			}
+
+			return nativeBoolToBooleanObject(hash.Delete(key))
+		},
+	},
+
+	"range": {
+		Fn: func(_ *object.Runtime, args ...object.Object) object.Object {
+			if len(args) < 1 || len(args) > 3 {
+				return newTypedError(object.TypeError, "wrong number of arguments. got=%d, want=1..3",
+					len(args))
+			}
+
+			bounds := make([]int64, len(args))
+			for i, arg := range args {
+				integer, ok := arg.(*object.Integer)
+				if !ok {
+					return newTypedError(object.TypeError, "arguments to `range` must be integers, got %s",
+						arg.Type().String())
+				}
+
+				bounds[i] = integer.Value
+			}
+
+			rng := &object.Range{Start: 0, End: bounds[0], Step: 1}
+
+			if len(bounds) > 1 {
+				rng.Start = bounds[0]
+				rng.End = bounds[1]
+			}
+
+			if len(bounds) > 2 {
+				rng.Step = bounds[2]
+			}
+
+			if rng.Step == 0 {
+				return newError("`range` step must not be zero")
+			}
+
+			return rng
+		},
+	},
+
+	// error creates an error value to throw, with an optional type
+	"error": {
+		Fn: func(_ *object.Runtime, args ...object.Object) object.Object {
+			if len(args) < 1 || len(args) > 2 {
+				return newTypedError(object.TypeError, "wrong number of arguments. got=%d, want=1..2",
+					len(args))
+			}
+
+			err := &object.Error{Kind: object.GenericError}
+
+			for i, arg := range args {
+				str, ok := arg.(*object.String)
+				if !ok {
+					return newTypedError(object.TypeError, "arguments to `error` must be strings, got %s",
+						arg.Type().String())
+				}
+
+				if i == 0 {
+					err.Message = str.Value
+				} else {
+					err.Kind = str.Value
+				}
+			}
+
+			return &object.ErrorValue{Error: err}
+		},
+	},
+
 	"println": {
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
 			for _, arg := range args {
-				fmt.Print(arg.Inspect(), " ")
+				fmt.Fprint(rt.Stdout, arg.Inspect(), " ")
 			}
-			fmt.Println()
+			fmt.Fprintln(rt.Stdout)
 
 			return nil
 		},
 	},
 
 	"print": {
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			for _, arg := range args {
+				fmt.Fprint(rt.Stdout, arg.Inspect(), " ")
+			}
+
+			return nil
+		},
+	},
+
+	"eprintln": {
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
 			for _, arg := range args {
-				fmt.Print(arg.Inspect(), " ")
+				fmt.Fprint(rt.Stderr, arg.Inspect(), " ")
 			}
+			fmt.Fprintln(rt.Stderr)
 
 			return nil
 		},
 	},
+
+	// input prints an optional prompt and reads a line, returning null at the
+	// end of the input
+	"input": {
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if len(args) > 1 {
+				return newTypedError(object.TypeError, "wrong number of arguments. got=%d, want=0 or 1",
+					len(args))
+			}
+
+			if len(args) == 1 {
+				prompt, ok := args[0].(*object.String)
+				if !ok {
+					return newTypedError(object.TypeError, "argument to `input` must be a string, got %s",
+						args[0].Type().String())
+				}
+
+				fmt.Fprint(rt.Stdout, prompt.Value)
+			}
+
+			return readLine(rt)
+		},
+	},
+
+	"readline": {
+		Fn: func(rt *object.Runtime, args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return newTypedError(object.TypeError, "wrong number of arguments. got=%d, want=0",
+					len(args))
+			}
+
+			return readLine(rt)
+		},
+	},
+}
+
+// readLine reads a line from the standard input of rt, or returns null at the
+// end of the input.
+func readLine(rt *object.Runtime) object.Object {
+	line, ok, err := rt.ReadLine()
+	if err != nil {
+		return newError("cannot read input: %s", err)
+	}
+
+	if !ok {
+		return NULL
+	}
+
+	return &object.String{Value: line}
 }
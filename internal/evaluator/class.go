@@ -0,0 +1,109 @@
+package evaluator
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/object"
+)
+
+func evalClassDefinition(node *ast.ClassDefinition, env *object.Environment) object.Object {
+	// the methods are closures of an environment holding the superclass,
+	// which super expressions refer to
+	classEnv := object.NewEnclosedEnvironment(env)
+
+	var superclass object.Object
+	if node.Superclass != nil {
+		superclass = Eval(node.Superclass, env)
+		if isError(superclass) {
+			return superclass
+		}
+
+		classEnv.Set(0, 0, superclass)
+	}
+
+	methods := make(map[string]*object.Function, len(node.Methods))
+	for _, method := range node.Methods {
+		methods[method.TokenLiteral()] = &object.Function{
+			Parameters: method.Parameters,
+			Body:       method.Body,
+			Env:        classEnv,
+		}
+	}
+
+	return newClass(node.Name.Value, superclass, methods)
+}
+
+// newClass creates a class, whose superclass is nil when it extends nothing.
+func newClass(name string, superclass object.Object, methods map[string]*object.Function) object.Object {
+	class := &object.Class{Name: name, Methods: methods}
+
+	if superclass != nil {
+		var ok bool
+		if class.Superclass, ok = superclass.(*object.Class); !ok {
+			return newTypedError(object.TypeError, "superclass must be a class, got %s", superclass.Type())
+		}
+	}
+
+	return class
+}
+
+// construct creates an instance of a class, passing args to its init method.
+func construct(rt *object.Runtime, class *object.Class, args []object.Object, call ast.Node) object.Object {
+	instance := object.NewClassInstance(class)
+
+	init, ok := class.Method("init")
+	if !ok {
+		if len(args) != 0 {
+			return newTypedError(object.TypeError, "wrong number of arguments: expected 0, got %d", len(args))
+		}
+
+		return instance
+	}
+
+	if result := callFunction(rt, init, instance, args, call); isError(result) {
+		return result
+	}
+
+	return instance
+}
+
+func evalSuperExpression(node *ast.SuperExpression, env *object.Environment) object.Object {
+	superclass := Eval(node.Class, env)
+	if isError(superclass) {
+		return superclass
+	}
+
+	self := Eval(node.Self, env)
+	if isError(self) {
+		return self
+	}
+
+	return superMethod(superclass, self, node.Method.Value)
+}
+
+// superMethod binds the method name of a superclass to self, skipping the
+// methods of self's own class that override it.
+func superMethod(superclass, self object.Object, name string) object.Object {
+	// newClass made sure it is a class
+	class := superclass.(*object.Class)
+
+	method, ok := class.Method(name)
+	if !ok {
+		return newTypedError(object.TypeError, "%s has no method %s", class.Name, name)
+	}
+
+	return &object.BoundMethod{Self: self, Method: method, Name: class.Name + "." + name}
+}
+
+func evalInstanceOf(left, right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.Class:
+		instance, ok := left.(*object.ClassInstance)
+		return nativeBoolToBooleanObject(ok && instance.Class.IsSubclassOf(right))
+
+	case *object.StructType:
+		instance, ok := left.(*object.Instance)
+		return nativeBoolToBooleanObject(ok && instance.Struct == right)
+	}
+
+	return newTypedError(object.TypeError, "right side of instanceof is not a class: %s", right.Type())
+}
@@ -0,0 +1,36 @@
+package evaluator
+
+import "sunbird/internal/object"
+
+func isComparisonOperator(operator string) bool {
+	switch operator {
+	case "<", ">", "<=", ">=":
+		return true
+	default:
+		return false
+	}
+}
+
+func evalComparisonExpression(operator string, left, right object.Object) object.Object {
+	result, ok := object.Compare(left, right)
+	if !ok {
+		return newTypedError(object.TypeError, "cannot compare %s %s %s", left.Type(), operator, right.Type())
+	}
+
+	if result == object.Unordered {
+		return FALSE
+	}
+
+	switch operator {
+	case "<":
+		return nativeBoolToBooleanObject(result < 0)
+	case ">":
+		return nativeBoolToBooleanObject(result > 0)
+	case "<=":
+		return nativeBoolToBooleanObject(result <= 0)
+	case ">=":
+		return nativeBoolToBooleanObject(result >= 0)
+	default:
+		return newTypedError(object.TypeError, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
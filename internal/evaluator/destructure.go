@@ -0,0 +1,116 @@
+package evaluator
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/object"
+)
+
+// destructure binds the variables of a declaration or parameter pattern to
+// the parts of val.
+func destructure(pattern ast.Pattern, val object.Object, env *object.Environment) *object.Error {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		setVariable(env, pattern, val)
+
+	case *ast.ArrayPattern:
+		elements, err := unpackArray(env.Runtime(), val, len(pattern.Elements), pattern.Rest != nil)
+		if err != nil {
+			return errorAt(err, pattern)
+		}
+
+		for i, element := range pattern.Elements {
+			if err := destructure(element, elements[i], env); err != nil {
+				return err
+			}
+		}
+
+		if pattern.Rest != nil {
+			setVariable(env, pattern.Rest, elements[len(elements)-1])
+		}
+
+	case *ast.HashPattern:
+		for i, key := range pattern.Keys {
+			field := unpackField(val, key.Value)
+			if err, ok := field.(*object.Error); ok {
+				return errorAt(err, key)
+			}
+
+			if err := destructure(pattern.Values[i], field, env); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// fitsArray reports whether val is an array of n elements, or of at least n
+// elements when rest is true.
+func fitsArray(val object.Object, n int, rest bool) bool {
+	array, ok := val.(*object.Array)
+	if !ok {
+		return false
+	}
+
+	if rest {
+		return len(array.Elements) >= n
+	}
+
+	return len(array.Elements) == n
+}
+
+// unpackArray returns the elements of the array val, which must have n of
+// them, or at least n when rest is true, in which case the elements after
+// the first n are returned as one last array.
+func unpackArray(rt *object.Runtime, val object.Object, n int, rest bool) ([]object.Object, *object.Error) {
+	if !fitsArray(val, n, rest) {
+		switch {
+		case val.Type() != object.ArrayObj:
+			return nil, newTypedError(object.TypeError, "expected an array, got %s", val.Type())
+		case rest:
+			return nil, newTypedError(object.TypeError, "expected an array of length at least %d, got %d", n, len(val.(*object.Array).Elements))
+		default:
+			return nil, newTypedError(object.TypeError, "expected an array of length %d, got %d", n, len(val.(*object.Array).Elements))
+		}
+	}
+
+	elements := val.(*object.Array).Elements
+	if !rest {
+		return elements, nil
+	}
+
+	others := &object.Array{Elements: append([]object.Object{}, elements[n:]...)}
+	if err := rt.Allocate(others); err != nil {
+		return nil, err
+	}
+
+	return append(elements[:n:n], others), nil
+}
+
+// unpackField returns the value of the key name of a hash, or of the field
+// name of the values that have fields, like instances.
+func unpackField(val object.Object, name string) object.Object {
+	switch val := val.(type) {
+	case *object.Hash:
+		if value, ok := val.Get(&object.String{Value: name}); ok {
+			return value
+		}
+
+		return newTypedError(object.TypeError, "hash has no key %s", name)
+
+	case *object.Instance, *object.ClassInstance, *object.Module, *object.ErrorValue:
+		return evalMember(val, name)
+	}
+
+	return newTypedError(object.TypeError, "expected a hash, got %s", val.Type())
+}
+
+// errorAt makes err point at the part of a pattern the value does not fit.
+func errorAt(err *object.Error, node ast.Node) *object.Error {
+	if !err.Pos.IsValid() {
+		err.Pos = node.Pos()
+		err.End = node.End()
+	}
+
+	return err
+}
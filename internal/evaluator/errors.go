@@ -6,7 +6,12 @@ import (
 )
 
 func newError(format string, a ...interface{}) *object.Error {
-	return &object.Error{Message: fmt.Sprintf(format, a...)}
+	return newTypedError(object.GenericError, format, a...)
+}
+
+// newTypedError creates an error of the given kind, like object.TypeError.
+func newTypedError(kind, format string, a ...interface{}) *object.Error {
+	return &object.Error{Message: fmt.Sprintf(format, a...), Kind: kind}
 }
 
 func isError(obj object.Object) bool {
@@ -16,3 +21,13 @@ func isError(obj object.Object) bool {
 
 	return false
 }
+
+// allocate counts the memory of obj against the allocation limit of rt,
+// returning the error in place of obj when the limit is exceeded.
+func allocate(rt *object.Runtime, obj object.Object) object.Object {
+	if err := rt.Allocate(obj); err != nil {
+		return err
+	}
+
+	return obj
+}
@@ -6,12 +6,31 @@ import (
 )
 
 var (
-	NULL  = &object.Null{}
-	TRUE  = &object.Boolean{Value: true}
-	FALSE = &object.Boolean{Value: false}
+	NULL     = &object.Null{}
+	TRUE     = &object.Boolean{Value: true}
+	FALSE    = &object.Boolean{Value: false}
+	BREAK    = &object.Break{}
+	CONTINUE = &object.Continue{}
 )
 
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	var result object.Object
+	if err := env.Runtime().Step(); err != nil {
+		result = err
+	} else {
+		result = evalNode(node, env)
+	}
+
+	// the innermost node an error comes from is where it happened
+	if err, ok := result.(*object.Error); ok && !err.Pos.IsValid() {
+		err.Pos = node.Pos()
+		err.End = node.End()
+	}
+
+	return result
+}
+
+func evalNode(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 	case *ast.Program:
 		return evalProgram(node.Statements, env)
@@ -22,6 +41,9 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
 
+	case *ast.InterpolatedString:
+		return evalInterpolatedString(node, env)
+
 	case *ast.IntegerLiteral:
 		return &object.Integer{Value: node.Value}
 
@@ -35,6 +57,10 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return NULL
 
 	case *ast.InfixExpression:
+		if node.Operator == "&&" || node.Operator == "||" {
+			return evalLogicalExpression(node, env)
+		}
+
 		left := Eval(node.Left, env)
 		if isError(left) {
 			return left
@@ -45,7 +71,11 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return right
 		}
 
-		return evalInfixExpression(node.Operator, left, right)
+		if node.Operator == "|>" {
+			return evalPipeExpression(node, left, right, env.Runtime())
+		}
+
+		return allocate(env.Runtime(), evalInfixExpression(node.Operator, left, right))
 
 	case *ast.PrefixExpression:
 		right := Eval(node.Right, env)
@@ -60,9 +90,36 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
 
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
+
 	case *ast.ForStatement:
 		return evalForStatement(node, env)
 
+	case *ast.ForInStatement:
+		return evalForInStatement(node, env)
+
+	case *ast.WhileStatement:
+		return evalWhileStatement(node, env)
+
+	case *ast.BreakStatement:
+		return BREAK
+
+	case *ast.ContinueStatement:
+		return CONTINUE
+
+	case *ast.ThrowStatement:
+		return evalThrowStatement(node, env)
+
+	case *ast.TryStatement:
+		return evalTryStatement(node, env)
+
+	case *ast.ImportStatement:
+		return evalImportStatement(node, env)
+
+	case *ast.ExportStatement:
+		return Eval(node.Statement, env)
+
 	case *ast.ReturnStatement:
 		val := Eval(node.ReturnValue, env)
 		if isError(val) {
@@ -71,27 +128,19 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return &object.ReturnValue{Value: val}
 
 	case *ast.VarStatement:
-		if _, ok := env.Get(node.Name.Value); ok {
-			return newError("Identifier '%s' has already been declared.", node.Name.Value)
-		}
-
 		val := Eval(node.Value, env)
 		if isError(val) {
 			return val
 		}
-		env.Set(node.Name.Value, val)
 
-	case *ast.AssignStatement:
-		if _, ok := env.Get(node.Name.Value); !ok {
-			return newError("Identifier '%s' has not been declared.", node.Name.Value)
-		}
-
-		val := Eval(node.Value, env)
-		if isError(val) {
-			return val
+		if node.Pattern == nil {
+			setVariable(env, node.Name, val)
+		} else if err := destructure(node.Pattern, val, env); err != nil {
+			return err
 		}
 
-		env.Set(node.Name.Value, val)
+	case *ast.AssignStatement:
+		return evalAssignStatement(node, env)
 
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
@@ -110,7 +159,7 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if len(args) == 1 && isError(args[0]) {
 			return args[0]
 		}
-		return applyFunction(function, args)
+		return applyFunction(env.Runtime(), function, args, node)
 
 	case *ast.ArrayLiteral:
 		elements := evalExpressions(node.Elements, env)
@@ -119,7 +168,22 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return elements[0]
 		}
 
-		return &object.Array{Elements: elements}
+		return allocate(env.Runtime(), &object.Array{Elements: elements})
+
+	case *ast.HashLiteral:
+		return evalHashLiteral(node, env)
+
+	case *ast.StructDefinition:
+		return evalStructDefinition(node)
+
+	case *ast.StructLiteral:
+		return evalStructLiteral(node, env)
+
+	case *ast.ClassDefinition:
+		return evalClassDefinition(node, env)
+
+	case *ast.SuperExpression:
+		return evalSuperExpression(node, env)
 
 	case *ast.IndexExpression:
 		left := Eval(node.Left, env)
@@ -133,6 +197,12 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		}
 
 		return evalIndexExpression(left, index)
+
+	case *ast.MemberExpression:
+		return evalMemberExpression(node, env)
+
+	case *ast.BadStatement, *ast.BadExpression:
+		return newError("cannot evaluate code containing syntax errors")
 	}
 	return nil
 }
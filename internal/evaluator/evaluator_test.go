@@ -1,14 +1,54 @@
 package evaluator_test
 
 import (
+	"bytes"
+	"context"
+	"fmt"
 	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sunbird/internal/ast"
+	"sunbird/internal/compiler"
 	"sunbird/internal/evaluator"
 	"sunbird/internal/lexer"
 	"sunbird/internal/object"
 	"sunbird/internal/parser"
+	"sunbird/internal/vm"
 	"testing"
 )
 
+// engine runs the programs of the tests, which pass with both the tree
+// walking evaluator and the bytecode VM.
+var engine string
+
+func TestMain(m *testing.M) {
+	for _, engine = range []string{"tree", "vm"} {
+		if code := m.Run(); code != 0 {
+			fmt.Printf("engine %s failed\n", engine)
+			os.Exit(code)
+		}
+	}
+}
+
+// run runs a program with the engine under test.
+func run(program *ast.Program, runtime *object.Runtime) object.Object {
+	if err := evaluator.ResolveModule(program); err != nil {
+		return err
+	}
+
+	if engine == "tree" {
+		return evaluator.Eval(program, object.NewModuleEnvironment(runtime))
+	}
+
+	c := compiler.New()
+	if err := c.Compile(program); err != nil {
+		return err
+	}
+
+	return vm.New(c.Bytecode(), runtime).Run()
+}
+
 func TestEvalIntegerExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -60,12 +100,11 @@ func TestEvalFloatExpression(t *testing.T) {
 }
 
 func testEval(input string) object.Object {
-	l := lexer.New(input)
+	l := lexer.New("", input)
 	p := parser.New(l)
 	program := p.ParseProgram()
-	env := object.NewEnvironment()
 
-	return evaluator.Eval(program, env)
+	return run(program, object.NewRuntime())
 }
 
 func testIntegerObject(t *testing.T, obj object.Object, expected int64) {
@@ -208,6 +247,46 @@ if (10 > 1) {
 	}
 }
 
+func TestStatementValues(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string // empty when the program has no value
+	}{
+		{`var f = func() { for x in [1, 2] { x } }; [f()]`, "[2]"},
+		{`var f = func() { for x in [1, 2, 3] { if x == 3 { break }; x } }; f()`, "2"},
+		{`var f = func() { for x in [1, 2, 3] { if x == 3 { continue }; x } }; f()`, "2"},
+		{`var f = func() { for x in [1] { var y = x } }; [f()]`, "[null]"},
+		{`var f = func() { for i = 0; i < 3; i = i + 1 { i * 10 } }; f()`, "20"},
+		{`var f = func() { var i = 0; while i < 3 { i = i + 1; i * 10 } }; f()`, "30"},
+		{`func() { while false {} }() == null`, "true"},
+		{`var f = func() { try { 5 } catch (e) { 6 } }; f()`, "5"},
+		{`var f = func() { try { throw "x" } catch (e) { 6 } }; f()`, "6"},
+		{`var f = func() { try { 5 } finally { 7 } }; f()`, "5"},
+		{`var f = func() { var x = 1 }; [f()]`, "[null]"},
+		{`var f = func() {}; [f()]`, "[null]"},
+		{`var f = func() { try { 5 } catch (e) { 6 }; var y = 1 }; f() == null`, "true"},
+		{`if true { for x in [1, 2] { x } }`, "2"},
+		{`try { 5 } catch (e) { 6 }`, "5"},
+		{`try { 5 } finally { var y = 1 }`, "5"},
+		{`for x in [1, 2] { x }`, "2"},
+		{`while false {}`, "null"},
+		{`var x = 1`, ""},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		got := ""
+		if evaluated != nil {
+			got = evaluated.Inspect()
+		}
+
+		if got != tt.expected {
+			t.Errorf("%s: wrong result. expected=%q, got=%q", tt.input, tt.expected, got)
+		}
+	}
+}
+
 func TestErrorHandling(t *testing.T) {
 	tests := []struct {
 		input           string
@@ -337,6 +416,248 @@ addTwo(2);`
 	testIntegerObject(t, testEval(input), 4)
 }
 
+func TestScopes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"var f = func() { g() }; var g = func() { 7 }; f()", "7"},
+		{"var x = 1; var f = func(x) { x * 10 }; f(2) + x", "21"},
+		{"if true { var y = 1 } else { var y = 2 }; y", "1"},
+		{"var fs = []; for i in [1, 2] { var j = i; fs = append(fs, func() { j }) }; fs[0]() + fs[1]()", "3"},
+		{"var f = func() { later }; f(); var later = 1", "ERROR: identifier not found: later"},
+		{"x; var x = 1", "ERROR: Identifier 'x' is used before its declaration."},
+		{"var a = 1; var a = 2", "ERROR: Identifier 'a' has already been declared."},
+		{"var a = 1; var f = func() { var a = 2 }", "ERROR: Identifier 'a' has already been declared."},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil {
+			t.Errorf("%s: Eval returned nil", tt.input)
+			continue
+		}
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: wrong result. expected=%q, got=%q",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestStructs(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"struct Point { x, y }; Point{x: 1, y: 2}", "Point{x: 1, y: 2}"},
+		{"struct Point { x, y }; Point{y: 2}", "Point{x: null, y: 2}"},
+		{"struct Point { x, y }; Point", "<struct Point>"},
+		{"struct Point { x, y }; var p = Point{x: 1, y: 2}; p.x + p.y", "3"},
+		{"struct Point { x, y }; var p = Point{x: 1, y: 2}; p.x = 5; p.y *= 3; p", "Point{x: 5, y: 6}"},
+		{"struct Box { v }; var a = Box{v: 1}; var b = a; b.v = 2; a.v", "2"},
+		{"struct Box { v }; var b = Box{v: Box{v: [1]}}; b.v.v[0] = 7; b", "Box{v: Box{v: [7]}}"},
+		{"struct Box { v }; [Box{v: 1} == Box{v: 1}, Box{v: 1} == Box{v: 2}]", "[true, false]"},
+		{"struct A { v }; struct B { v }; A{v: 1} == B{v: 1}", "false"},
+		{"struct Box { v }; if Box{v: 1}.v == 1 { 10 }", "ERROR: identifier not found: v"},
+		{"struct Box { v }; var b = Box{v: 1}; if b.v == 1 { 10 }", "10"},
+		{"var f = func() { struct P { x }; P{x: 1} }; f().x", "1"},
+		{"var f = func() { p }; struct P { x }; var p = P{x: 1}; f()", "P{x: 1}"},
+		{"struct Point { x, y }; Point{z: 1}", "ERROR: Point has no field z"},
+		{"struct Point { x, y }; Point{x: 1}.z", "ERROR: Point has no field z"},
+		{"struct Point { x, y }; var p = Point{}; p.z = 1", "ERROR: Point has no field z"},
+		{"var n = 1; n{x: 1}", "ERROR: not a struct: INTEGER"},
+		{"var h = {}; h.x = 1", "ERROR: cannot assign to field x of HASH"},
+		{"struct Point { x }; struct Point { y }", "ERROR: Identifier 'Point' has already been declared."},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil {
+			t.Errorf("%s: Eval returned nil", tt.input)
+			continue
+		}
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: wrong result. expected=%q, got=%q",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestClasses(t *testing.T) {
+	animals := `
+class Animal {
+  init(name) { self.name = name }
+  speak() { self.name + " makes a sound" }
+  rename(name) { self.name = name; self }
+}
+
+class Dog extends Animal {
+  init(name, breed) {
+    super.init(name)
+    self.breed = breed
+  }
+
+  speak() { super.speak() + " (woof)" }
+}
+`
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{animals + `Dog("Rex", "lab")`, "Dog{name: Rex, breed: lab}"},
+		{animals + `Dog("Rex", "lab").speak()`, "Rex makes a sound (woof)"},
+		{animals + `Dog("Rex", "lab").rename("Max").speak()`, "Max makes a sound (woof)"},
+		{animals + `var speak = Dog("Rex", "lab").speak; speak()`, "Rex makes a sound (woof)"},
+		{animals + `var d = Dog("Rex", "lab"); [d instanceof Dog, d instanceof Animal, Animal("a") instanceof Dog]`, "[true, true, false]"},
+		{animals + `[Animal, Dog("Rex", "lab").speak]`, "[<class Animal>, <method Dog.speak>]"},
+		{animals + `"Rex" |> Animal`, "Animal{name: Rex}"},
+		{"class Empty {}; Empty()", "Empty{}"},
+		{"class A { f() { 1 } }; class B extends A {}; B().f()", "1"},
+		{"class A { init() { return 5 } }; A()", "A{}"},
+		{"class C { init() { self.n = 0 } inc() { self.n += 1; self } }; C().inc().inc().n", "2"},
+		{"class C { f() { func() { self } } }; var c = C(); c.f()() == c", "true"},
+		{"class C { f() { C } }; C().f()", "<class C>"},
+		{"var make = func() { var n = 0; class C { inc() { n += 1; n } }; C() }; var c = make(); c.inc(); c.inc()", "2"},
+		{"class A { f() { 1 } }; var make = func() { class B extends A { f() { super.f() + 1 } }; B() }; make().f()", "2"},
+		{"struct P { x }; [P{x: 1} instanceof P, 1 instanceof P]", "[true, false]"},
+		{"class A {}; A() == A()", "false"},
+		{"class A {}; A().x", "ERROR: A has no field or method x"},
+		{"class A {}; class B extends A { g() { super.g() } }; B().g()", "ERROR: A has no method g"},
+		{"class A { init(x) {} }; A()", "ERROR: wrong number of arguments: expected 1, got 0"},
+		{"class A {}; A(1)", "ERROR: wrong number of arguments: expected 0, got 1"},
+		{"class A { f(x) {} }; A().f()", "ERROR: wrong number of arguments: expected 1, got 0"},
+		{"var x = 1; class A extends x {}", "ERROR: superclass must be a class, got INTEGER"},
+		{"1 instanceof 1", "ERROR: right side of instanceof is not a class: INTEGER"},
+		{"class A { f() {} }; A.f", "ERROR: CLASS has no field f"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil {
+			t.Errorf("%s: Eval returned nil", tt.input)
+			continue
+		}
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: wrong result. expected=%q, got=%q",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	describe := `
+var describe = func(v) {
+  match v {
+    0 => "zero",
+    1 | 2 | 3 => "small",
+    -1 => "minus one",
+    "a" | "b" => "letter",
+    null => "null",
+    [] => "empty",
+    [x] => "one ${x}",
+    [x, [y, _]] => "nested ${x + y}",
+    [x, y] => "pair ${x + y}",
+    n if n > 10 => {
+      var d = n * 2
+      "big ${d}"
+    }
+    _ => "other",
+  }
+}
+`
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{describe + "describe(0)", "zero"},
+		{describe + "describe(2)", "small"},
+		{describe + "describe(2.0)", "small"},
+		{describe + "describe(-1)", "minus one"},
+		{describe + `describe("b")`, "letter"},
+		{describe + "describe(null)", "null"},
+		{describe + "describe([])", "empty"},
+		{describe + "describe([5])", "one 5"},
+		{describe + "describe([1, [2, 3]])", "nested 3"},
+		{describe + "describe([1, 2])", "pair 3"},
+		{describe + "describe([1, 2, 3])", "ERROR: type mismatch: ARRAY > INTEGER"},
+		{describe + "describe(11)", "big 22"},
+		{describe + "describe(7)", "other"},
+		{"var x = 1; match 2 { x => x } + x", "3"},
+		{"match 1 { 1 => {} }", "null"},
+		{"var f = func(x) { match x { 1 => { return 10 } _ => 0 }; 20 }; [f(1), f(2)]", "[10, 20]"},
+		{"var fs = []; for i in [1, 2] { match i { n => { fs = append(fs, func() { n }) } } }; [fs[0](), fs[1]()]", "[1, 2]"},
+		{"var n = 0; for i in range(5) { n += match i % 2 { 0 => 1, _ => 0 } }; n", "3"},
+		{"match 5 { 1 => 1 }", "ERROR: no pattern matches 5"},
+		{`match [1, "a"] { [_, 1] => 1 }`, "ERROR: no pattern matches [1, a]"},
+		{"match 5 { n if n.x => 1 }", "ERROR: INTEGER has no field x"},
+		{`var r = ""; try { match 5 {} } catch (e) { r = e.type + ": " + e.message }; r`, "MatchError: no pattern matches 5"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil {
+			t.Errorf("%s: Eval returned nil", tt.input)
+			continue
+		}
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: wrong result. expected=%q, got=%q",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"var [a, b] = [1, 2]; [b, a]", "[2, 1]"},
+		{"var [a, _, c] = [1, 2, 3]; a + c", "4"},
+		{"var [first, ...rest] = [1, 2, 3]; [first, rest]", "[1, [2, 3]]"},
+		{"var [first, ...rest] = [1]; rest", "[]"},
+		{"var [[a, b], [c]] = [[1, 2], [3]]; a + b + c", "6"},
+		{`var {name, age} = {"name": "Ann", "age": 30}; [name, age]`, "[Ann, 30]"},
+		{`var {name: n, tags: [first, _]} = {"name": "Ann", "tags": ["x", "y"]}; [n, first]`, "[Ann, x]"},
+		{"struct P { x, y }; var {x, y} = P{x: 1, y: 2}; x + y", "3"},
+		{"class C { init() { self.n = 1 } }; var {n} = C(); n", "1"},
+		{"var f = func() { var [a, b] = [1, 2]; a + b }; f()", "3"},
+		{"var fs = []; for i in [1, 2] { var [n] = [i]; fs = append(fs, func() { n }) }; [fs[0](), fs[1]()]", "[1, 2]"},
+		{"var add = func([a, b]) { a + b }; add([1, 2])", "3"},
+		{`var greet = func({name}, greeting) { greeting + " " + name }; greet({"name": "Ann"}, "hi")`, "hi Ann"},
+		{"var f = func([a, ...rest]) { func() { a + len(rest) } }; f([1, 2, 3])()", "3"},
+		{"class C { f([a, b]) { a * b } }; C().f([2, 3])", "6"},
+		{"var f = func([a, b]) { a }; f", "func([a, b]) {\na\n}"},
+		{"match [1, 2, 3] { [a, ...rest] => rest }", "[2, 3]"},
+		{"match [1] { [a, b, ...rest] => rest, _ => 0 }", "0"},
+		{"var [a, b] = [1, 2, 3]", "ERROR: expected an array of length 2, got 3"},
+		{"var [a, b, ...rest] = [1]", "ERROR: expected an array of length at least 2, got 1"},
+		{"var [a] = 1", "ERROR: expected an array, got INTEGER"},
+		{`var {name} = {"age": 1}`, "ERROR: hash has no key name"},
+		{"struct P { x }; var {y} = P{x: 1}", "ERROR: P has no field y"},
+		{"var {x} = 1", "ERROR: expected a hash, got INTEGER"},
+		{"var {a} = [1]", "ERROR: expected a hash, got ARRAY"},
+		{"var f = func([a]) { a }; f(1)", "ERROR: expected an array, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil {
+			t.Errorf("%s: Eval returned nil", tt.input)
+			continue
+		}
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: wrong result. expected=%q, got=%q",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
 func TestStringLiteral(t *testing.T) {
 	input := `"Hello World!"`
 
@@ -441,9 +762,98 @@ func TestArrayIndexExpressions(t *testing.T) {
 		{"var myArray = [1, 2, 3]; myArray[2];", 3},
 		{"var myArray = [1, 2, 3]; myArray[0] + myArray[1] + myArray[2];", 6},
 		{"var myArray = [1, 2, 3]; var i = myArray[0]; myArray[i]", 2},
-		{"[1, 2, 3][3]", nil},
 		{"[1, 2, 3][-1]", 3},
+		{"[1, 2, 3][-3]", 1},
+		{"[1, 2, 3][3]", "ERROR: index out of range: 3"},
+		{"[1][-5]", "ERROR: index out of range: -5"},
+		{"[][0]", "ERROR: index out of range: 0"},
+		{`var r = ""; try { [1][-5] } catch (e) { r = e.type + ": " + e.message }; r`, "IndexError: index out of range: -5"},
+	}
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			if evaluated.Inspect() != expected {
+				t.Errorf("%s: wrong result. expected=%q, got=%q", tt.input, expected, evaluated.Inspect())
+			}
+		}
+	}
+}
+
+func TestHashLiterals(t *testing.T) {
+	input := `var two = "two";
+{
+  "one": 10 - 9,
+  two: 1 + 1,
+  "thr" + "ee": 6 / 2,
+  4: 4,
+  2.5: 5,
+  true: 6,
+  false: 7,
+  null: 8
+}`
+
+	evaluated := testEval(input)
+
+	result, ok := evaluated.(*object.Hash)
+	if !ok {
+		t.Fatalf("Eval didn't return Hash. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	expected := []struct {
+		key   object.Hashable
+		value int64
+	}{
+		{&object.String{Value: "one"}, 1},
+		{&object.String{Value: "two"}, 2},
+		{&object.String{Value: "three"}, 3},
+		{&object.Integer{Value: 4}, 4},
+		{&object.Float{Value: 2.5}, 5},
+		{evaluator.TRUE, 6},
+		{evaluator.FALSE, 7},
+		{evaluator.NULL, 8},
+	}
+
+	if result.Len() != len(expected) {
+		t.Fatalf("Hash has wrong num of pairs. got=%d", result.Len())
 	}
+
+	for i, pair := range result.Entries() {
+		if pair.Key.Inspect() != expected[i].key.Inspect() {
+			t.Errorf("pair %d has wrong key. got=%s, want=%s",
+				i, pair.Key.Inspect(), expected[i].key.Inspect())
+		}
+
+		value, ok := result.Get(expected[i].key)
+		if !ok {
+			t.Errorf("no pair for given key in Pairs")
+			continue
+		}
+
+		testIntegerObject(t, value, expected[i].value)
+	}
+}
+
+func TestHashIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`{"foo": 5}["foo"]`, 5},
+		{`{"foo": 5}["bar"]`, nil},
+		{`var key = "foo"; {"foo": 5}[key]`, 5},
+		{`{}["foo"]`, nil},
+		{`{5: 5}[5]`, 5},
+		{`{true: 5}[true]`, 5},
+		{`{false: 5}[false]`, 5},
+		{`{null: 5}[null]`, 5},
+		{`{1: 5}[1.0]`, 5},
+		{`{2.0: 5}[2]`, 5},
+	}
+
 	for _, tt := range tests {
 		evaluated := testEval(tt.input)
 		integer, ok := tt.expected.(int)
@@ -455,3 +865,938 @@ func TestArrayIndexExpressions(t *testing.T) {
 		}
 	}
 }
+
+func TestHashErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`{"name": "Sunbird"}[func(x) { x }];`, "unusable as hash key: FUNCTION"},
+		{`{[1]: 2}`, "unusable as hash key: ARRAY"},
+		{`keys([1])`, "argument to `keys` must be a hash, got ARRAY"},
+		{`has({}, [])`, "unusable as hash key: ARRAY"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong error message. expected=%q, got=%q",
+				tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestHashBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`keys({"a": 1, "b": 2})`, "[a, b]"},
+		{`values({"a": 1, "b": 2})`, "[1, 2]"},
+		{`keys({})`, "[]"},
+		{`has({"a": 1}, "a")`, "true"},
+		{`has({"a": 1}, "b")`, "false"},
+		{`var h = {"a": 1, "b": 2}; delete(h, "a"); h`, "{b: 2}"},
+		{`delete({"a": 1}, "b")`, "false"},
+		{`len({"a": 1, "b": 2})`, "2"},
+		{`{"b": 1, "a": 2, "b": 3}`, "{b: 3, a: 2}"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil {
+			t.Errorf("%s: Eval returned nil", tt.input)
+			continue
+		}
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: wrong result. expected=%q, got=%q",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestValueEquality(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"1 == 1", true},
+		{"1 != 1", false},
+		{"1 == 2", false},
+		{"1 == 1.0", true},
+		{"2.5 == 2.5", true},
+		{"2.5 != 2", true},
+		{`"a" == "a"`, true},
+		{`"a" != "a"`, false},
+		{`"a" == "b"`, false},
+		{`"1" == 1`, false},
+		{"null == null", true},
+		{"null == false", false},
+		{"[1, 2, 3] == [1, 2, 3]", true},
+		{"[1, 2, 3] == [1, 2]", false},
+		{`[1, [2, "x"]] == [1.0, [2, "x"]]`, true},
+		{`{"a": 1, "b": 2} == {"b": 2, "a": 1}`, true},
+		{`{"a": 1} == {"a": 2}`, false},
+		{"var f = func() {}; f == f", true},
+		{"func() {} == func() {}", false},
+		{"len == len", true},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testBooleanObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestValuesContainingThemselves(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"var a = [0]; a[0] = a; a", "[[...]]"},
+		{"var a = [1, 2]; a[1] = [a]; a", "[1, [[...]]]"},
+		{`var h = {"x": 1}; h["self"] = h; h`, "{x: 1, self: {...}}"},
+		{`var h = {}; h["list"] = [h]; h`, "{list: [{...}]}"},
+		{"struct N { next }; var n = N{next: null}; n.next = n; n", "N{next: {...}}"},
+		{"class C { init() { self.me = self } }; C()", "C{me: {...}}"},
+		{"var b = [1]; [b, b]", "[[1], [1]]"},
+		{"var a = [0]; a[0] = a; a == a", "true"},
+		{"var a = [0]; a[0] = a; var b = [0]; b[0] = b; a == b", "true"},
+		{"var a = [0]; a[0] = a; var b = [1]; b[0] = b; [a == [a], a != [1]]", "[true, true]"},
+		{`var h = {}; h["h"] = h; var g = {}; g["h"] = g; h == g`, "true"},
+		{"var a = [0]; a[0] = a; var b = [0]; b[0] = b; [a < b, a <= b]", "[false, true]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil {
+			t.Errorf("%s: Eval returned nil", tt.input)
+			continue
+		}
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: wrong result. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestStringAndArrayComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{`"a" < "b"`, true},
+		{`"b" < "a"`, false},
+		{`"abc" > "abd"`, false},
+		{`"abc" <= "abc"`, true},
+		{`"b" >= "abc"`, true},
+		{`"" < "a"`, true},
+		{"[1, 2] < [1, 3]", true},
+		{"[1, 2] < [1, 2, 0]", true},
+		{"[2] > [1, 9]", true},
+		{"[1, 2] <= [1, 2]", true},
+		{`["b"] >= ["a", "z"]`, true},
+		{"var nan = 0.0 / 0.0; nan < 1", false},
+		{"var nan = 0.0 / 0.0; [nan] < [1]", false},
+		{"var nan = 0.0 / 0.0; [nan] > [1]", false},
+		{"var nan = 0.0 / 0.0; [nan] <= [nan]", false},
+		{"var nan = 0.0 / 0.0; [nan] >= [nan]", false},
+		{"var nan = 0.0 / 0.0; [1, nan] >= [1, 2]", false},
+		{"var nan = 0.0 / 0.0; [1, nan] < [2, 2]", true},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testBooleanObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestComparisonErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`"a" < 1`, "type mismatch: STRING < INTEGER"},
+		{`[1] < ["a"]`, "cannot compare ARRAY < ARRAY"},
+		{"[1] * [2]", "unknown operator: ARRAY * ARRAY"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong error message. expected=%q, got=%q",
+				tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestRelationalOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"1 <= 2", true},
+		{"2 <= 2", true},
+		{"3 <= 2", false},
+		{"1 >= 2", false},
+		{"2 >= 2", true},
+		{"3 >= 2", true},
+		{"1.5 < 2.5", true},
+		{"1.5 > 2.5", false},
+		{"2.5 <= 2.5", true},
+		{"2.4 >= 2.5", false},
+		{"1 < 1.5", true},
+		{"2 > 1.5", true},
+		{"2 <= 2.0", true},
+		{"1.9 >= 2", false},
+		{"-1 <= -1.0", true},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testBooleanObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestFloatOperatorErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"1.5 + true", "type mismatch: FLOAT + BOOLEAN"},
+		{"null <= 1.5", "type mismatch: NULL <= FLOAT"},
+		{"[1] + 1.5", "type mismatch: ARRAY + FLOAT"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong error message. expected=%q, got=%q",
+				tt.expectedMessage, errObj.Message)
+		}
+	}
+}
+
+func TestWhileStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"var i = 0; while i < 10 { i = i + 1 }; i", 10},
+		{"var i = 0; while false { i = i + 1 }; i", 0},
+		{"var i = 0; while true { i = i + 1; if i == 5 { break } }; i", 5},
+		{
+			"var i = 0; var sum = 0; while i < 10 { i = i + 1; if i > 3 { continue }; sum = sum + i }; sum",
+			6,
+		},
+		{
+			"var sum = 0; for var i = 0; i < 10; i = i + 1 { if i == 2 { continue }; if i == 5 { break }; sum = sum + i }; sum",
+			8,
+		},
+		{
+			`
+var count = 0
+var i = 0
+while i < 3 {
+  i = i + 1
+  var j = 0
+  while true {
+    j = j + 1
+    if j > 2 { break }
+    count = count + 1
+  }
+}
+count`,
+			6,
+		},
+		{
+			`
+var find = func(limit) {
+  var i = 0
+  while true {
+    if i == limit { return i * 10 }
+    i = i + 1
+  }
+}
+find(4)`,
+			40,
+		},
+		{
+			`
+var n = 0
+while n < 3 {
+  var f = func() { 1 }
+  n = n + f()
+}
+n`,
+			3,
+		},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestForInStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`var sum = 0; for x in [1, 2, 3] { sum = sum + x }; sum`, "6"},
+		{`var out = []; for i, x in [10, 20] { out = append(out, i, x) }; out`, "[0, 10, 1, 20]"},
+		{`var out = ""; for c in "héllo" { out = c + out }; out`, "olléh"},
+		{`var out = []; for i, c in "ab" { out = append(out, i, c) }; out`, "[0, a, 1, b]"},
+		{`var out = []; for k in {"a": 1, "b": 2} { out = append(out, k) }; out`, "[a, b]"},
+		{`var out = []; for k, v in {"a": 1, "b": 2} { out = append(out, k, v) }; out`, "[a, 1, b, 2]"},
+		{`var out = []; for i in range(5) { out = append(out, i) }; out`, "[0, 1, 2, 3, 4]"},
+		{`var out = []; for i in range(2, 5) { out = append(out, i) }; out`, "[2, 3, 4]"},
+		{`var out = []; for i in range(10, 0, -3) { out = append(out, i) }; out`, "[10, 7, 4, 1]"},
+		{`var out = []; for i in range(0, 10, 4) { out = append(out, i) }; out`, "[0, 4, 8]"},
+		{`var out = []; for i in range(5, 0) { out = append(out, i) }; out`, "[]"},
+		{`var out = []; for i, x in range(3, 6) { out = append(out, i) }; out`, "[0, 1, 2]"},
+		{`var n = 0; for x in range(1000000000000) { if x == 3 { break }; n = n + 1 }; n`, "3"},
+		{`var n = 0; for x in [1, 2, 3, 4] { if x == 2 || x == 4 { continue }; n = n + x }; n`, "4"},
+		{`for x in [1, 2] { var y = x }; len([])`, "0"},
+		{`var f = func() { for x in [1, 2, 3] { if x == 2 { return x * 100 } } }; f()`, "200"},
+		{`var x = "outer"; for x in [1, 2] { x }; x`, "outer"},
+		{`for x in [1] { x }; x`, "ERROR: identifier not found: x"},
+		{`len(range(0, 10, 3))`, "4"},
+		{`len(range(-9223372036854775807, 9223372036854775807))`, "9223372036854775807"},
+		{`len(range(9223372036854775807, -9223372036854775807, -9223372036854775807))`, "2"},
+		{`len(range(-9223372036854775807, 9223372036854775807, 9223372036854775807))`, "2"},
+		{`var out = []; for x in range(9223372036854775806, 9223372036854775807) { out = append(out, x) }; out`, "[9223372036854775806]"},
+		{`range(1, 4)`, "range(1, 4, 1)"},
+		{`range(1, 2, 0)`, "ERROR: `range` step must not be zero"},
+		{`range("a")`, "ERROR: arguments to `range` must be integers, got STRING"},
+		{`for x in 5 { x }`, "ERROR: not iterable: INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil {
+			t.Errorf("%s: Eval returned nil", tt.input)
+			continue
+		}
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: wrong result. expected=%q, got=%q",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestStringEscapes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"a\tb"`, "a\tb"},
+		{`"line\n" + "next"`, "line\nnext"},
+		{`len("\u{1F426}")`, "4"},
+		{"`C:\\path\\n`", `C:\path\n`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil {
+			t.Errorf("%s: Eval returned nil", tt.input)
+			continue
+		}
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: wrong result. expected=%q, got=%q",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestStringInterpolation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`var name = "Sunbird"; "Hello ${name}!"`, "Hello Sunbird!"},
+		{`var items = [1, 2, 3]; "you have ${len(items)} items"`, "you have 3 items"},
+		{`"${1 + 2}${"x"}"`, "3x"},
+		{`var h = {"a": [1, 2]}; "h: ${h}, a: ${h["a"]}"`, "h: {a: [1, 2]}, a: [1, 2]"},
+		{`var who = "world"; "outer ${"inner ${who}"}"`, "outer inner world"},
+		{`var f = func(x) { "<${x}>" }; f(1.5)`, "<1.5>"},
+		{`"${null} ${true}"`, "null true"},
+		{`"${missing}"`, "ERROR: identifier not found: missing"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil {
+			t.Errorf("%s: Eval returned nil", tt.input)
+			continue
+		}
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: wrong result. expected=%q, got=%q",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestArithmeticOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"7 % 3", "1"},
+		{"-7 % 3", "2"},
+		{"7 % -3", "-2"},
+		{"-7 % -3", "-1"},
+		{"-7 % 2", "1"},
+		{"7.5 % 2", "1.5"},
+		{"-7.5 % 2", "0.5"},
+		{"7.5 % -2", "-0.5"},
+		{"7 ~/ 2", "3"},
+		{"-7 ~/ 2", "-4"},
+		{"7 ~/ -2", "-4"},
+		{"-8 ~/ 2", "-4"},
+		{"7.5 ~/ 2", "3"},
+		{"2 ** 10", "1024"},
+		{"2 ** 3 ** 2", "512"},
+		{"-2 ** 2", "-4"},
+		{"2 ** -1", "0.5"},
+		{"2.5 ** 2", "6.25"},
+		{"4 ** 0.5", "2"},
+		{"2 * 3 ** 2", "18"},
+		{"6 & 3", "2"},
+		{"6 | 3", "7"},
+		{"6 ^ 3", "5"},
+		{"~5", "-6"},
+		{"1 << 4", "16"},
+		{"-16 >> 2", "-4"},
+		{"1 | 2 ^ 3 & 4", "3"},
+		{"1 + 1 << 2", "8"},
+		{"5 % 0", "ERROR: division by zero"},
+		{"5 ~/ 0", "ERROR: division by zero"},
+		{"1 << -1", "ERROR: negative shift count: -1"},
+		{"1.5 & 1", "ERROR: unknown operator: FLOAT & INTEGER"},
+		{"~1.5", "ERROR: unknown operator: ~FLOAT"},
+		{`"a" % 2`, "ERROR: unknown operator: STRING % INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil {
+			t.Errorf("%s: Eval returned nil", tt.input)
+			continue
+		}
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: wrong result. expected=%q, got=%q",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestAssignStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"var x = 1; x = 5; x", "5"},
+		{"var x = 1; x += 5; x", "6"},
+		{"var x = 10; x -= 3; x", "7"},
+		{"var x = 4; x *= 2.5; x", "10"},
+		{"var x = 9; x /= 2; x", "4"},
+		{"var x = 9; x %= 4; x", "1"},
+		{`var s = "a"; s += "b"; s`, "ab"},
+		{"var arr = [1, 2, 3]; arr[0] = 10; arr", "[10, 2, 3]"},
+		{"var arr = [1, 2, 3]; arr[-1] = 30; arr", "[1, 2, 30]"},
+		{"var arr = [1, 2, 3]; arr[1] += 5; arr", "[1, 7, 3]"},
+		{"var grid = [[0, 0], [0, 0]]; grid[1][0] = 1; grid", "[[0, 0], [1, 0]]"},
+		{"var a = [1]; var b = a; b[0] = 2; a", "[2]"},
+		{"var a = [1, 2]; var b = append(a, 3); b[0] = 9; a", "[1, 2]"},
+		{`var h = {}; h["k"] = 1; h["k"] += 1; h`, "{k: 2}"},
+		{`var h = {"a": [1]}; h["a"][0] = 5; h`, "{a: [5]}"},
+		{"var set = func(arr) { arr[0] = 99 }; var a = [1]; set(a); a", "[99]"},
+		{"x = 1", "ERROR: Identifier 'x' has not been declared."},
+		{"x += 1", "ERROR: Identifier 'x' has not been declared."},
+		{"var arr = [1]; arr[1] = 2", "ERROR: index out of range: 1"},
+		{"var arr = [1]; arr[-2] = 2", "ERROR: index out of range: -2"},
+		{`var arr = [1]; arr["a"] = 2`, "ERROR: array index must be an integer, got STRING"},
+		{`var h = {}; h[[1]] = 2`, "ERROR: unusable as hash key: ARRAY"},
+		{`var s = "abc"; s[0] = "x"`, "ERROR: index assignment not supported: STRING"},
+		{`var h = {}; h["a"] += 1`, "ERROR: type mismatch: NULL + INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil {
+			t.Errorf("%s: Eval returned nil", tt.input)
+			continue
+		}
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: wrong result. expected=%q, got=%q",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestLogicalOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"true && true", "true"},
+		{"true && false", "false"},
+		{"false || true", "true"},
+		{"false || false", "false"},
+		{`null || "default"`, "default"},
+		{`var name = "sunbird"; name || "default"`, "sunbird"},
+		{`"" || "empty"`, "empty"},
+		{"0 && 5", "0"},
+		{"1 && 5", "5"},
+		{"null && 1 / 0", "null"},
+		{"true || 1 / 0", "true"},
+		{"var x = null; x != null && x[0] > 0", "false"},
+		{"var called = false; var f = func() { called = true }; false && f(); called", "false"},
+		{"var called = false; var f = func() { called = true }; false || f(); called", "true"},
+		{"true && missing", "ERROR: identifier not found: missing"},
+		{"missing || true", "ERROR: identifier not found: missing"},
+		{`1 && "a" || "b"`, "a"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil {
+			t.Errorf("%s: Eval returned nil", tt.input)
+			continue
+		}
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: wrong result. expected=%q, got=%q",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestErrorPositionsAndStack(t *testing.T) {
+	input := `var inner = func(x) {
+  return x + y
+}
+var outer = func(x) { inner(x * 2) }
+var run = func() { 3 |> outer }
+run()
+var y = 1`
+
+	errObj, ok := testEval(input).(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned")
+	}
+
+	if errObj.Pos.Line != 2 || errObj.Pos.Col != 14 || errObj.End.Col != 15 {
+		t.Errorf("wrong error position. got=%d:%d-%d:%d",
+			errObj.Pos.Line, errObj.Pos.Col, errObj.End.Line, errObj.End.Col)
+	}
+
+	expected := []struct {
+		function  string
+		line, col int
+	}{
+		{"inner", 4, 23},
+		{"outer", 5, 20},
+		{"run", 6, 1},
+	}
+
+	if len(errObj.Stack) != len(expected) {
+		t.Fatalf("wrong number of frames. expected=%d, got=%d", len(expected), len(errObj.Stack))
+	}
+
+	for i, frame := range errObj.Stack {
+		if frame.Function != expected[i].function {
+			t.Errorf("frame %d: wrong function. expected=%q, got=%q", i, expected[i].function, frame.Function)
+		}
+
+		if frame.Pos.Line != expected[i].line || frame.Pos.Col != expected[i].col {
+			t.Errorf("frame %d: wrong call site. expected=%d:%d, got=%d:%d",
+				i, expected[i].line, expected[i].col, frame.Pos.Line, frame.Pos.Col)
+		}
+	}
+}
+
+func TestErrorOutsideFunctionHasNoStack(t *testing.T) {
+	errObj, ok := testEval("var f = func(a) { a }\n\nf(1, 2)").(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned")
+	}
+
+	if errObj.Pos.Line != 3 || errObj.Pos.Col != 1 || len(errObj.Stack) != 0 {
+		t.Errorf("wrong location. got=%d:%d with %d frames", errObj.Pos.Line, errObj.Pos.Col, len(errObj.Stack))
+	}
+}
+
+func TestTryCatchFinally(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`var r = 0; try { r = 1 } catch (e) { r = 2 }; r`, "1"},
+		{`var r = ""; try { 1 / 0 } catch (e) { r = e.type + ": " + e.message }; r`, "ZeroDivisionError: division by zero"},
+		{`var f = func() { later }; var r = ""; try { f() } catch (e) { r = e.type }; var later = 1; r`, "NameError"},
+		{`var r = ""; try { true + 1 } catch (e) { r = e.type }; r`, "TypeError"},
+		{`var a = [1]; var r = ""; try { a[3] = 1 } catch (e) { r = e.type }; r`, "IndexError"},
+		{`var r = ""; try { len(1) } catch (e) { r = e.message }; r`, "argument to `len` not supported, got INTEGER"},
+		{`var r = ""; try { throw "boom" } catch (e) { r = e.type + " " + e.message }; r`, "Error boom"},
+		{`var r = ""; try { throw error("bad", "ValueError") } catch (e) { r = e.type + " " + e.message }; r`, "ValueError bad"},
+		{`var r = ""; try { throw [1, 2] } catch (e) { r = e.message }; r`, "[1, 2]"},
+		{`var log = []; try { log = append(log, 1) } finally { log = append(log, 2) }; log`, "[1, 2]"},
+		{`var log = []; try { 1 / 0 } catch (e) { log = append(log, 1) } finally { log = append(log, 2) }; log`, "[1, 2]"},
+		{`var f = func() { try { return 1 } finally { 2 } }; f()`, "1"},
+		{`var f = func() { try { return 1 } finally { return 2 } }; f()`, "2"},
+		{`var i = 0; while true { try { i = i + 1; if i >= 12 { break } } finally { i = i + 10 } }; i`, "22"},
+		{
+			`var f = func() { throw error("inner", "ValueError") }
+var g = func() { f() }
+var r = []; try { g() } catch (e) { r = e.stack }; r`,
+			"[f at 2:18, g at 3:19]",
+		},
+		{
+			`class A { init() { self.check() } check() { throw "bad" } }
+class B extends A { init() { super.init() } }
+var r = []; try { B() } catch (e) { r = e.stack }; r`,
+			"[check at 1:20, super.init at 2:30, B at 3:19]",
+		},
+		{
+			`var f = func() { later }
+var r = ""
+try { try { f() } catch (e) { throw e } } catch (err) { r = err.message }
+var later = 1
+r`,
+			"identifier not found: later",
+		},
+		{`var e = "outer"; try { throw "x" } catch (e) { e }; e`, "outer"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil {
+			t.Errorf("%s: Eval returned nil", tt.input)
+			continue
+		}
+
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("%s: wrong result. expected=%q, got=%q",
+				tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestUncaughtErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+		expectedKind    string
+	}{
+		{`throw "boom"`, "boom", object.GenericError},
+		{`try { 1 / 0 } catch (e) { throw e }`, "division by zero", object.ZeroDivisionError},
+		{`try { 1 } finally { throw error("in finally", "ValueError") }`, "in finally", "ValueError"},
+		{`try { throw "a" } catch (e) { e.missing }`, "ERROR_VALUE has no field missing", object.TypeError},
+	}
+
+	for _, tt := range tests {
+		errObj, ok := testEval(tt.input).(*object.Error)
+		if !ok {
+			t.Errorf("%s: no error object returned", tt.input)
+			continue
+		}
+
+		if errObj.Message != tt.expectedMessage || errObj.Kind != tt.expectedKind {
+			t.Errorf("%s: wrong error. expected=%s %q, got=%s %q",
+				tt.input, tt.expectedKind, tt.expectedMessage, errObj.Kind, errObj.Message)
+		}
+	}
+}
+
+// testEvalFile evaluates input as the file main.sb in dir, after writing the
+// given modules next to it.
+func testEvalFile(t *testing.T, modules map[string]string, input string, searchPath ...string) object.Object {
+	dir := t.TempDir()
+
+	for name, src := range modules {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l := lexer.New(filepath.Join(dir, "main.sb"), input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %q", p.Errors())
+	}
+
+	runtime := object.NewRuntime()
+	for _, searchDir := range searchPath {
+		runtime.SearchPath = append(runtime.SearchPath, filepath.Join(dir, searchDir))
+	}
+
+	return run(program, runtime)
+}
+
+func TestInputOutput(t *testing.T) {
+	tests := []struct {
+		input    string
+		stdin    string
+		stdout   string
+		stderr   string
+		expected interface{}
+	}{
+		{`println("a", 1); print("b")`, "", "a 1 \nb ", "", nil},
+		{`eprintln("oops")`, "", "", "oops \n", nil},
+		{`var name = input("name? "); "hi " + name`, "ada\n", "name? ", "", "hi ada"},
+		{`input()`, "", "", "", nil},
+		{`[readline(), readline(), readline()]`, "one\r\ntwo", "", "", "[one, two, null]"},
+		{`readline(1)`, "", "", "", "wrong number of arguments. got=1, want=0"},
+		{`input(1)`, "", "", "", "argument to `input` must be a string, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+
+		runtime := object.NewRuntime()
+		runtime.Stdin = strings.NewReader(tt.stdin)
+		runtime.Stdout = &stdout
+		runtime.Stderr = &stderr
+
+		evaluated := run(parser.New(lexer.New("", tt.input)).ParseProgram(), runtime)
+
+		if stdout.String() != tt.stdout || stderr.String() != tt.stderr {
+			t.Errorf("%s: wrong output. got=%q, %q, want=%q, %q", tt.input, stdout.String(), stderr.String(), tt.stdout, tt.stderr)
+		}
+
+		switch expected := tt.expected.(type) {
+		case nil:
+			if evaluated != nil && evaluated != evaluator.NULL {
+				t.Errorf("%s: expected null, got=%T (%+v)", tt.input, evaluated, evaluated)
+			}
+		case string:
+			var got string
+			switch evaluated := evaluated.(type) {
+			case *object.Error:
+				got = evaluated.Message
+			case *object.String:
+				got = evaluated.Value
+			default:
+				got = evaluated.Inspect()
+			}
+
+			if got != expected {
+				t.Errorf("%s: expected %q, got=%q", tt.input, expected, got)
+			}
+		}
+	}
+}
+
+func TestLimits(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		input    string
+		limits   object.Limits
+		ctx      context.Context
+		expected string
+	}{
+		{"while (true) {}", object.Limits{MaxSteps: 1000}, nil, "LimitError: step limit of 1000 exceeded"},
+		{"for i = 0; i < 10; i = i + 1 {}", object.Limits{MaxSteps: 1000}, nil, ""},
+		{"while (true) {}", object.Limits{}, cancelled, "LimitError: execution cancelled: context canceled"},
+		{
+			"var f = func(n) { return f(n + 1) }; f(0)",
+			object.Limits{MaxDepth: 50}, nil, "RecursionError: stack overflow",
+		},
+		{
+			"var f = func(n) { if (n == 0) { return 0 } return f(n - 1) }; f(50)",
+			object.Limits{MaxDepth: 51}, nil, "",
+		},
+		{
+			`var f = func(n) { return f(n + 1) }
+			try { f(0) } catch (e) { throw e.message + " caught" }`,
+			object.Limits{MaxDepth: 50}, nil, "Error: stack overflow caught",
+		},
+		{
+			`var s = ""; while (true) { try { s = s + "abcd" } catch (e) {} finally { s = "" } }`,
+			object.Limits{MaxAlloc: 100}, nil, "LimitError: allocation limit of 100 bytes exceeded",
+		},
+		{"var a = [1, 2, 3]; append(a, 4)", object.Limits{MaxAlloc: 150}, nil, ""},
+		{
+			"var a = [1, 2, 3, 4, 5]; var b = append(a, a, a)",
+			object.Limits{MaxAlloc: 150}, nil, "LimitError: allocation limit of 150 bytes exceeded",
+		},
+		{"var x = 1; \"${x}${x}${x}\"", object.Limits{MaxAlloc: 2}, nil, "LimitError: allocation limit of 2 bytes exceeded"},
+		{
+			`var s = "x"; while true { s += s }`,
+			object.Limits{MaxAlloc: 1000}, nil, "LimitError: allocation limit of 1000 bytes exceeded",
+		},
+		{
+			`var a = ["x"]; while true { a[0] += a[0] }`,
+			object.Limits{MaxAlloc: 1000}, nil, "LimitError: allocation limit of 1000 bytes exceeded",
+		},
+	}
+
+	for _, tt := range tests {
+		runtime := object.NewRuntime()
+		runtime.Limits = tt.limits
+		runtime.Reset(tt.ctx)
+
+		evaluated := run(parser.New(lexer.New("", tt.input)).ParseProgram(), runtime)
+
+		err, ok := evaluated.(*object.Error)
+		if tt.expected == "" {
+			if ok {
+				t.Errorf("%s: unexpected error: %s", tt.input, err.Message)
+			}
+
+			continue
+		}
+
+		if !ok {
+			t.Errorf("%s: expected error %q, got=%T (%+v)", tt.input, tt.expected, evaluated, evaluated)
+			continue
+		}
+
+		if got := err.Kind + ": " + err.Message; got != tt.expected {
+			t.Errorf("%s: wrong error. got=%q, want=%q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestStackTraceIsTruncated(t *testing.T) {
+	runtime := object.NewRuntime()
+	runtime.Limits.MaxDepth = 500
+
+	evaluated := run(parser.New(lexer.New("", "var f = func() { f() }; f()")).ParseProgram(), runtime)
+
+	err, ok := evaluated.(*object.Error)
+	if !ok || err.Kind != object.RecursionError {
+		t.Fatalf("expected a RecursionError, got=%T (%+v)", evaluated, evaluated)
+	}
+
+	if len(err.Stack) != object.MaxStackFrames {
+		t.Errorf("wrong stack length. got=%d, want=%d", len(err.Stack), object.MaxStackFrames)
+	}
+}
+
+func TestImports(t *testing.T) {
+	modules := map[string]string{
+		"math.sb": `
+			export var pi = 3;
+			export var square = func(x) { return x * x };
+			export var [e, tau] = [2, 6];
+			var secret = 42;
+		`,
+		"lib/util.sb": `
+			import { square } from "../math";
+			export var quad = func(x) { return square(square(x)) };
+		`,
+		"counter.sb": `
+			var count = 0;
+			export var next = func() { count = count + 1; return count };
+		`,
+		"vendor/strings.sb": `export var greeting = "hi";`,
+	}
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`import "math"; math.square(math.pi)`, 9},
+		{`import "./math.sb" as m; m.pi`, 3},
+		{`import { pi, square } from "./math"; square(pi) + pi`, 12},
+		{`import { quad } from "./lib/util"; quad(2)`, 16},
+		{`import "./counter" as a; import "./counter" as b; a.next(); b.next()`, 2},
+		{`import "strings"; strings.greeting`, "hi"},
+		{`import { e, tau } from "./math"; tau - e`, 4},
+		{`import "math"; var {pi, square} = math; square(pi)`, 9},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEvalFile(t, modules, tt.input, "vendor")
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			str, ok := evaluated.(*object.String)
+			if !ok || str.Value != expected {
+				t.Errorf("%s: expected %q, got=%T (%+v)", tt.input, expected, evaluated, evaluated)
+			}
+		}
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	modules := map[string]string{
+		"math.sb":   `export var pi = 3; var secret = 42;`,
+		"a.sb":      `import "./b"; export var a = 1;`,
+		"b.sb":      `import "./a"; export var b = 2;`,
+		"broken.sb": `var x = ;`,
+		"fails.sb":  `export var x = 1 / 0;`,
+		"my-lib.sb": `export var x = 1;`,
+	}
+
+	tests := []struct {
+		input           string
+		expectedMessage string
+		expectedKind    string
+	}{
+		{`import "./missing"`, `cannot find module "./missing"`, object.ImportError},
+		{`import "./a"`, "import cycle: a.sb -> b.sb -> a.sb", object.ImportError},
+		{`import { secret } from "./math"`, "module math has no export secret", object.ImportError},
+		{`import "./math"; math.secret`, "module math has no export secret", object.NameError},
+		{`var math = 1; import "./math"`, "Identifier 'math' has already been declared.", object.NameError},
+		{`import "./my-lib"`, `module name "my-lib" is not an identifier, use import "./my-lib" as name`,
+			object.ImportError},
+		{`import "./fails"`, "division by zero", object.ZeroDivisionError},
+	}
+
+	for _, tt := range tests {
+		errObj, ok := testEvalFile(t, modules, tt.input).(*object.Error)
+		if !ok {
+			t.Errorf("%s: no error object returned", tt.input)
+			continue
+		}
+
+		if errObj.Message != tt.expectedMessage || errObj.Kind != tt.expectedKind {
+			t.Errorf("%s: wrong error. expected=%s %q, got=%s %q",
+				tt.input, tt.expectedKind, tt.expectedMessage, errObj.Kind, errObj.Message)
+		}
+	}
+
+	errObj, ok := testEvalFile(t, modules, `import "./broken"`).(*object.Error)
+	if !ok || errObj.Kind != object.ImportError || !strings.HasPrefix(errObj.Message, `syntax error in module "./broken"`) {
+		t.Errorf("wrong error for module with syntax errors. got=%+v", errObj)
+	}
+}
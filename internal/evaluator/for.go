@@ -8,9 +8,12 @@ import (
 func evalForStatement(fs *ast.ForStatement, env *object.Environment) object.Object {
 	loopEnv := object.NewEnclosedEnvironment(env)
 
-	// This sucks but uhhh I don't know how to do it better
+	// Assigning to an unknown variable declares it in the loop, the resolver
+	// binds it there
 	if assign, ok := fs.Init.(*ast.AssignStatement); ok {
-		loopEnv.Set(assign.Name.Value, NULL)
+		if ident, ok := assign.Target.(*ast.Identifier); ok && ident.Binding.Depth == 0 {
+			setVariable(loopEnv, ident, NULL)
+		}
 	}
 
 	if fs.Init != nil {
@@ -34,16 +37,21 @@ func evalForStatement(fs *ast.ForStatement, env *object.Environment) object.Obje
 			}
 		}
 
-		result = Eval(fs.Body, loopEnv)
-		if isError(result) {
-			return result
-		}
+		evaluated := Eval(fs.Body, object.NewEnclosedEnvironment(loopEnv))
 
-		// handle return statements
-		if result != nil && result.Type() == object.ReturnValueObj {
-			return result
+		if evaluated != nil {
+			switch evaluated.Type() {
+			case object.ErrorObj, object.ReturnValueObj:
+				return evaluated
+			case object.BreakObj:
+				return result
+			case object.ContinueObj:
+				evaluated = result
+			}
 		}
 
+		result = evaluated
+
 		if fs.Update != nil {
 			updateResult := Eval(fs.Update, loopEnv)
 			if isError(updateResult) {
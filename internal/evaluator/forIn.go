@@ -0,0 +1,64 @@
+package evaluator
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/object"
+)
+
+func evalForInStatement(fs *ast.ForInStatement, env *object.Environment) object.Object {
+	iterable := Eval(fs.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+
+	it, ok := iterable.(object.Iterable)
+	if !ok {
+		return newTypedError(object.TypeError, "not iterable: %s", iterable.Type().String())
+	}
+
+	// A single loop variable walks the keys of a hash, but the values of
+	// everything else
+	_, keysOnly := iterable.(*object.Hash)
+
+	iterator := it.Iterator()
+
+	var result object.Object = NULL
+
+	for {
+		key, value, ok := iterator.Next()
+		if !ok {
+			break
+		}
+
+		// The loop variables live in a fresh environment for every iteration so
+		// they never leak into the enclosing one
+		iterEnv := object.NewEnclosedEnvironment(env)
+
+		switch {
+		case fs.Index != nil:
+			setVariable(iterEnv, fs.Index, key)
+			setVariable(iterEnv, fs.Item, value)
+		case keysOnly:
+			setVariable(iterEnv, fs.Item, key)
+		default:
+			setVariable(iterEnv, fs.Item, value)
+		}
+
+		evaluated := Eval(fs.Body, iterEnv)
+
+		if evaluated != nil {
+			switch evaluated.Type() {
+			case object.ErrorObj, object.ReturnValueObj:
+				return evaluated
+			case object.BreakObj:
+				return result
+			case object.ContinueObj:
+				continue
+			}
+		}
+
+		result = evaluated
+	}
+
+	return result
+}
@@ -1,34 +1,81 @@
 package evaluator
 
-import "sunbird/internal/object"
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/object"
+)
 
-func applyFunction(fn object.Object, args []object.Object) object.Object {
+// applyFunction calls fn with args, in the runtime of the caller. The call
+// node is recorded in the stack trace of errors raised inside of the
+// function, unless it is nil because the host application made the call.
+func applyFunction(rt *object.Runtime, fn object.Object, args []object.Object, call ast.Node) object.Object {
 	switch fn := fn.(type) {
 	case *object.Function:
-		if len(args) != len(fn.Parameters) {
-			return newError("wrong number of arguments: expected %d, got %d", len(fn.Parameters), len(args))
-		}
+		return callFunction(rt, fn, nil, args, call)
+
+	case *object.BoundMethod:
+		return callFunction(rt, fn.Method, fn.Self, args, call)
 
-		extendedEnv := extendFunctionEnv(fn, args)
-		evaluated := Eval(fn.Body, extendedEnv)
-		return unwrapReturnValue(evaluated)
+	case *object.Class:
+		return construct(rt, fn, args, call)
 
 	case *object.Builtin:
-		return fn.Fn(args...)
+		return allocate(rt, fn.Fn(rt, args...))
 
 	default:
-		return newError("not a function: %s", fn.Type().String())
+		return newTypedError(object.TypeError, "not a function: %s", fn.Type().String())
+	}
+}
+
+// callFunction runs the body of a function. Methods are called with the
+// instance they belong to as self.
+func callFunction(rt *object.Runtime, fn *object.Function, self object.Object, args []object.Object, call ast.Node) object.Object {
+	if len(args) != len(fn.Parameters) {
+		return newTypedError(object.TypeError, "wrong number of arguments: expected %d, got %d", len(fn.Parameters), len(args))
+	}
+
+	if err := rt.Enter(); err != nil {
+		return err
+	}
+
+	var evaluated object.Object
+
+	if extendedEnv, err := extendFunctionEnv(fn, self, args); err != nil {
+		evaluated = err
+	} else {
+		evaluated = unwrapReturnValue(Eval(fn.Body, extendedEnv))
+	}
+
+	// a body ending with a statement that has no value, like a declaration,
+	// returns null
+	if evaluated == nil {
+		evaluated = NULL
+	}
+
+	rt.Leave()
+
+	if err, ok := evaluated.(*object.Error); ok && call != nil {
+		err.AddFrame(object.Frame{Function: calleeName(call), Pos: call.Pos()})
 	}
+
+	return evaluated
 }
 
-func extendFunctionEnv(fn *object.Function, args []object.Object) *object.Environment {
+func extendFunctionEnv(fn *object.Function, self object.Object, args []object.Object) (*object.Environment, *object.Error) {
 	env := object.NewEnclosedEnvironment(fn.Env)
 
+	// the resolver gives self the first slot of methods
+	if self != nil {
+		env.Set(0, 0, self)
+	}
+
 	for i, param := range fn.Parameters {
-		env.Set(param.Value, args[i])
+		if err := destructure(param, args[i], env); err != nil {
+			return nil, err
+		}
 	}
 
-	return env
+	return env, nil
 }
 
 func unwrapReturnValue(obj object.Object) object.Object {
@@ -38,3 +85,26 @@ func unwrapReturnValue(obj object.Object) object.Object {
 
 	return obj
 }
+
+// calleeName returns the name the function of a call was referred to by.
+func calleeName(call ast.Node) string {
+	var callee ast.Expression
+
+	switch call := call.(type) {
+	case *ast.CallExpression:
+		callee = call.Function
+	case *ast.InfixExpression: // a |> f
+		callee = call.Right
+	}
+
+	switch callee := callee.(type) {
+	case *ast.Identifier:
+		return callee.Value
+	case *ast.MemberExpression: // a method, or a function of a module
+		return callee.Property.Value
+	case *ast.SuperExpression:
+		return callee.String()
+	}
+
+	return "<anonymous>"
+}
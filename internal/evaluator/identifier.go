@@ -6,13 +6,27 @@ import (
 )
 
 func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object {
-	if val, ok := env.Get(node.Value); ok {
-		return val
+	switch node.Binding.Kind {
+	case ast.Variable:
+		if val, ok := env.Get(node.Binding.Depth, node.Binding.Slot); ok {
+			return val
+		}
+
+	case ast.Builtin:
+		return builtins[node.Value]
 	}
 
-	if builtin, ok := builtins[node.Value]; ok {
-		return builtin
+	// a variable of an enclosing function used before its declaration
+	return newTypedError(object.NameError, "identifier not found: %s", node.Value)
+}
+
+// setVariable stores val in the variable ident refers to. Nothing, like what
+// println returns, is stored as null since empty slots are undeclared
+// variables.
+func setVariable(env *object.Environment, ident *ast.Identifier, val object.Object) {
+	if val == nil {
+		val = NULL
 	}
 
-	return newError("identifier not found: %s", node.Value)
+	env.Set(ident.Binding.Depth, ident.Binding.Slot, val)
 }
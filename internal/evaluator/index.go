@@ -7,27 +7,45 @@ func evalIndexExpression(left, index object.Object) object.Object {
 	case left.Type() == object.ArrayObj && index.Type() == object.IntegerObj:
 		return evalArrayIndexExpression(left, index)
 
+	case left.Type() == object.HashObj:
+		return evalHashIndexExpression(left, index)
+
 	default:
-		return newError("index operator not supported: %s", left.Type().String())
+		return newTypedError(object.TypeError, "index operator not supported: %s", left.Type().String())
 	}
 }
 
 func evalArrayIndexExpression(left, index object.Object) object.Object {
 	array, ok := left.(*object.Array)
 	if !ok {
-		return newError("index operator not supported: %s", left.Type())
+		return newTypedError(object.TypeError, "index operator not supported: %s", left.Type())
 	}
 
+	// negative indexes count from the end
 	idx := index.(*object.Integer).Value
-	maxIdx := int64(len(array.Elements) - 1)
-
-	if idx > maxIdx {
-		return NULL
+	if idx < 0 {
+		idx += int64(len(array.Elements))
 	}
 
-	if idx < 0 {
-		return array.Elements[maxIdx+1+idx]
+	if idx < 0 || idx >= int64(len(array.Elements)) {
+		return newTypedError(object.IndexError, "index out of range: %d", index.(*object.Integer).Value)
 	}
 
 	return array.Elements[idx]
 }
+
+func evalHashIndexExpression(hash, index object.Object) object.Object {
+	hashObject := hash.(*object.Hash)
+
+	key, ok := index.(object.Hashable)
+	if !ok {
+		return newTypedError(object.TypeError, "unusable as hash key: %s", index.Type().String())
+	}
+
+	value, ok := hashObject.Get(key)
+	if !ok {
+		return NULL
+	}
+
+	return value
+}
@@ -1,23 +1,21 @@
 package evaluator
 
-import "sunbird/internal/object"
+import (
+	"math"
+	"sunbird/internal/ast"
+	"sunbird/internal/object"
+)
 
 func evalInfixExpression(operator string, left, right object.Object) object.Object {
 	switch {
-	case operator == "&&":
-		return nativeBoolToBooleanObject(isTruthy(left) && isTruthy(right))
-
-	case operator == "||":
-		return nativeBoolToBooleanObject(isTruthy(left) || isTruthy(right))
-
-	case operator == "|>":
-		return evalPipeExpression(left, right)
-
 	case operator == "==":
-		return nativeBoolToBooleanObject(left == right)
+		return nativeBoolToBooleanObject(object.Equals(left, right))
 
 	case operator == "!=":
-		return nativeBoolToBooleanObject(left != right)
+		return nativeBoolToBooleanObject(!object.Equals(left, right))
+
+	case operator == "instanceof":
+		return evalInstanceOf(left, right)
 
 	case left.Type() == object.IntegerObj && right.Type() == object.IntegerObj:
 		return evalIntegerInfixExpression(operator, left, right)
@@ -28,9 +26,13 @@ func evalInfixExpression(operator string, left, right object.Object) object.Obje
 	case left.Type() == object.FloatObj || right.Type() == object.FloatObj:
 		return evalFloatInfixExpression(operator, left, right)
 
+	case left.Type() == object.ArrayObj && right.Type() == object.ArrayObj:
+		return evalArrayInfixExpression(operator, left, right)
+
 	// TODO: this probably should be a different error
 	case left.Type() != right.Type():
-		return newError(
+		return newTypedError(
+			object.TypeError,
 			"type mismatch: %s %s %s",
 			left.Type().String(),
 			operator,
@@ -38,7 +40,8 @@ func evalInfixExpression(operator string, left, right object.Object) object.Obje
 		)
 
 	default:
-		return newError(
+		return newTypedError(
+			object.TypeError,
 			"unknown operator: %s %s %s",
 			left.Type().String(),
 			operator,
@@ -60,33 +63,69 @@ func evalIntegerInfixExpression(operator string, left, right object.Object) obje
 		return &object.Integer{Value: leftVal * rightVal}
 	case "/":
 		if rightVal == 0 {
-			return newError("division by zero")
+			return newTypedError(object.ZeroDivisionError, "division by zero")
 		}
 
 		return &object.Integer{Value: leftVal / rightVal}
+	case "%":
+		if rightVal == 0 {
+			return newTypedError(object.ZeroDivisionError, "division by zero")
+		}
+
+		return &object.Integer{Value: floorMod(leftVal, rightVal)}
+	case "~/":
+		if rightVal == 0 {
+			return newTypedError(object.ZeroDivisionError, "division by zero")
+		}
+
+		return &object.Integer{Value: floorDiv(leftVal, rightVal)}
+	case "**":
+		if rightVal < 0 {
+			return &object.Float{Value: math.Pow(float64(leftVal), float64(rightVal))}
+		}
+
+		return &object.Integer{Value: intPow(leftVal, rightVal)}
+	case "&":
+		return &object.Integer{Value: leftVal & rightVal}
+	case "|":
+		return &object.Integer{Value: leftVal | rightVal}
+	case "^":
+		return &object.Integer{Value: leftVal ^ rightVal}
+	case "<<":
+		if rightVal < 0 {
+			return newError("negative shift count: %d", rightVal)
+		}
+
+		return &object.Integer{Value: leftVal << rightVal}
+	case ">>":
+		if rightVal < 0 {
+			return newError("negative shift count: %d", rightVal)
+		}
+
+		return &object.Integer{Value: leftVal >> rightVal}
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
 	case ">":
 		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
 
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newTypedError(object.TypeError, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 }
 
 func evalFloatInfixExpression(operator string, left, right object.Object) object.Object {
-	var leftVal, rightVal float64
-
-	if left.Type() == object.IntegerObj {
-		leftVal = float64(left.(*object.Integer).Value)
-	} else {
-		leftVal = left.(*object.Float).Value
+	leftVal, ok := toFloat(left)
+	if !ok {
+		return newTypedError(object.TypeError, "type mismatch: %s %s %s", left.Type(), operator, right.Type())
 	}
 
-	if right.Type() == object.IntegerObj {
-		rightVal = float64(right.(*object.Integer).Value)
-	} else {
-		rightVal = right.(*object.Float).Value
+	rightVal, ok := toFloat(right)
+	if !ok {
+		return newTypedError(object.TypeError, "type mismatch: %s %s %s", left.Type(), operator, right.Type())
 	}
 
 	switch operator {
@@ -98,19 +137,96 @@ func evalFloatInfixExpression(operator string, left, right object.Object) object
 		return &object.Float{Value: leftVal * rightVal}
 	case "/":
 		return &object.Float{Value: leftVal / rightVal}
+	case "%":
+		return &object.Float{Value: floorModFloat(leftVal, rightVal)}
+	case "~/":
+		return &object.Float{Value: math.Floor(leftVal / rightVal)}
+	case "**":
+		return &object.Float{Value: math.Pow(leftVal, rightVal)}
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
 	case ">":
 		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case "<=":
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case ">=":
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
 	default:
-		return NULL
+		return newTypedError(object.TypeError, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// floorDiv divides rounding towards negative infinity, unlike Go's / which
+// truncates towards zero.
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+
+	return q
+}
+
+// floorMod is the remainder of floorDiv, so that it has the sign of b like
+// a - b * (a ~/ b), unlike Go's % which has the sign of a.
+func floorMod(a, b int64) int64 {
+	r := a % b
+	if r != 0 && (r < 0) != (b < 0) {
+		r += b
+	}
+
+	return r
+}
+
+// floorModFloat is floorMod for floats.
+func floorModFloat(a, b float64) float64 {
+	r := math.Mod(a, b)
+	if r != 0 && (r < 0) != (b < 0) {
+		r += b
+	}
+
+	return r
+}
+
+// intPow raises base to a non-negative exponent by repeated squaring.
+func intPow(base, exp int64) int64 {
+	result := int64(1)
+
+	for exp > 0 {
+		if exp&1 == 1 {
+			result *= base
+		}
+
+		base *= base
+		exp >>= 1
+	}
+
+	return result
+}
+
+// toFloat widens an Integer or Float operand to a float64.
+func toFloat(obj object.Object) (float64, bool) {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return float64(obj.Value), true
+	case *object.Float:
+		return obj.Value, true
+	default:
+		return 0, false
 	}
 }
 
 func evalStringInfixExpression(operator string, left, right object.Object) object.Object {
-	if operator != "+" && operator != "==" && operator != "!=" && operator != "&&" &&
-		operator != "||" {
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	if isComparisonOperator(operator) {
+		if left.Type() != right.Type() {
+			return newTypedError(object.TypeError, "type mismatch: %s %s %s", left.Type(), operator, right.Type())
+		}
+
+		return evalComparisonExpression(operator, left, right)
+	}
+
+	if operator != "+" && operator != "==" && operator != "!=" {
+		return newTypedError(object.TypeError, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
 	}
 
 	leftVal := left.Inspect()
@@ -119,14 +235,22 @@ func evalStringInfixExpression(operator string, left, right object.Object) objec
 	return &object.String{Value: leftVal + rightVal}
 }
 
-func evalPipeExpression(left, right object.Object) object.Object {
+func evalArrayInfixExpression(operator string, left, right object.Object) object.Object {
+	if isComparisonOperator(operator) {
+		return evalComparisonExpression(operator, left, right)
+	}
+
+	return newTypedError(object.TypeError, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
+}
+
+func evalPipeExpression(node *ast.InfixExpression, left, right object.Object, rt *object.Runtime) object.Object {
 	switch fn := right.(type) {
-	case *object.Function:
-		return applyFunction(fn, []object.Object{left})
+	case *object.Function, *object.BoundMethod, *object.Class:
+		return applyFunction(rt, fn, []object.Object{left}, node)
 
 	case *object.Builtin:
-		return fn.Fn(left)
+		return allocate(rt, fn.Fn(rt, left))
 	}
 
-	return newError("right side of pipe operator is not a function: %s", right.Type())
+	return newTypedError(object.TypeError, "right side of pipe operator is not a function: %s", right.Type())
 }
@@ -0,0 +1,24 @@
+package evaluator
+
+import (
+	"strings"
+	"sunbird/internal/ast"
+	"sunbird/internal/object"
+)
+
+func evalInterpolatedString(is *ast.InterpolatedString, env *object.Environment) object.Object {
+	var out strings.Builder
+
+	for _, part := range is.Parts {
+		value := Eval(part, env)
+		if isError(value) {
+			return value
+		}
+
+		if value != nil {
+			out.WriteString(value.Inspect())
+		}
+	}
+
+	return allocate(env.Runtime(), &object.String{Value: out.String()})
+}
@@ -0,0 +1,26 @@
+package evaluator
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/object"
+)
+
+// evalLogicalExpression evaluates && and || with short-circuiting. The result
+// is the operand that decided the outcome rather than a boolean, so
+// `name || "default"` evaluates to name when it is truthy.
+func evalLogicalExpression(node *ast.InfixExpression, env *object.Environment) object.Object {
+	left := Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	if node.Operator == "&&" && !isTruthy(left) {
+		return left
+	}
+
+	if node.Operator == "||" && isTruthy(left) {
+		return left
+	}
+
+	return Eval(node.Right, env)
+}
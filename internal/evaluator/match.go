@@ -0,0 +1,103 @@
+package evaluator
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/object"
+)
+
+func evalMatchExpression(node *ast.MatchExpression, env *object.Environment) object.Object {
+	subject := Eval(node.Subject, env)
+	if isError(subject) {
+		return subject
+	}
+
+	for _, arm := range node.Arms {
+		armEnv := object.NewEnclosedEnvironment(env)
+
+		matched, err := matchAny(arm.Patterns, subject, armEnv)
+		if err != nil {
+			return err
+		}
+
+		if !matched {
+			continue
+		}
+
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnv)
+			if isError(guard) {
+				return guard
+			}
+
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+
+		result := Eval(arm.Body, armEnv)
+		if result == nil {
+			return NULL
+		}
+
+		return result
+	}
+
+	return noMatch(subject)
+}
+
+func matchAny(patterns []ast.Pattern, val object.Object, env *object.Environment) (bool, *object.Error) {
+	for _, pattern := range patterns {
+		if matched, err := matchPattern(pattern, val, env); matched || err != nil {
+			return matched, err
+		}
+	}
+
+	return false, nil
+}
+
+// matchPattern reports whether val matches pattern, binding the variables of
+// the pattern in env.
+func matchPattern(pattern ast.Pattern, val object.Object, env *object.Environment) (bool, *object.Error) {
+	switch pattern := pattern.(type) {
+	case *ast.WildcardPattern:
+		return true, nil
+
+	case *ast.Identifier:
+		setVariable(env, pattern, val)
+		return true, nil
+
+	case *ast.LiteralPattern:
+		return object.Equals(evalNode(pattern.Value, env), val), nil
+
+	case *ast.ArrayPattern:
+		n := len(pattern.Elements)
+		if !fitsArray(val, n, pattern.Rest != nil) {
+			return false, nil
+		}
+
+		elements, err := unpackArray(env.Runtime(), val, n, pattern.Rest != nil)
+		if err != nil {
+			return false, err
+		}
+
+		for i, element := range pattern.Elements {
+			if matched, err := matchPattern(element, elements[i], env); !matched || err != nil {
+				return false, err
+			}
+		}
+
+		if pattern.Rest != nil {
+			setVariable(env, pattern.Rest, elements[n])
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// noMatch returns the error raised when no arm of a match expression matches
+// val.
+func noMatch(val object.Object) *object.Error {
+	return newTypedError(object.MatchError, "no pattern matches %s", val.Inspect())
+}
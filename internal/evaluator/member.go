@@ -0,0 +1,73 @@
+package evaluator
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/diagnostic"
+	"sunbird/internal/object"
+)
+
+func evalMemberExpression(node *ast.MemberExpression, env *object.Environment) object.Object {
+	obj := Eval(node.Object, env)
+	if isError(obj) {
+		return obj
+	}
+
+	return evalMember(obj, node.Property.Value)
+}
+
+func evalMember(obj object.Object, name string) object.Object {
+	switch obj := obj.(type) {
+	case *object.ErrorValue:
+		if field, ok := errorField(obj.Error, name); ok {
+			return field
+		}
+
+	case *object.Instance:
+		if index, ok := obj.Struct.FieldIndex(name); ok {
+			return obj.Fields[index]
+		}
+
+		return newTypedError(object.TypeError, "%s has no field %s", obj.Struct.Name, name)
+
+	case *object.ClassInstance:
+		if val, ok := obj.Fields.Get(&object.String{Value: name}); ok {
+			return val
+		}
+
+		if method, ok := obj.Class.Method(name); ok {
+			return &object.BoundMethod{Self: obj, Method: method, Name: obj.Class.Name + "." + name}
+		}
+
+		return newTypedError(object.TypeError, "%s has no field or method %s", obj.Class.Name, name)
+
+	case *object.Module:
+		if val, ok := obj.Exports[name]; ok {
+			return val
+		}
+
+		return newTypedError(object.NameError, "module %s has no export %s", obj.Name, name)
+	}
+
+	return newTypedError(object.TypeError, "%s has no field %s", obj.Type(), name)
+}
+
+func errorField(err *object.Error, name string) (object.Object, bool) {
+	switch name {
+	case "message":
+		return &object.String{Value: err.Message}, true
+
+	case "type":
+		return &object.String{Value: err.Kind}, true
+
+	case "stack":
+		stack := &object.Array{Elements: []object.Object{}}
+		for _, frame := range err.Stack {
+			line := frame.Function + " at " + diagnostic.Location(frame.Pos)
+			stack.Elements = append(stack.Elements, &object.String{Value: line})
+		}
+
+		return stack, true
+	}
+
+	return nil, false
+}
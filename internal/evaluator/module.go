@@ -0,0 +1,75 @@
+package evaluator
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/module"
+	"sunbird/internal/object"
+	"sunbird/internal/resolver"
+)
+
+func evalImportStatement(node *ast.ImportStatement, env *object.Environment) object.Object {
+	imported := importModule(node, env)
+
+	module, ok := imported.(*object.Module)
+	if !ok {
+		return imported
+	}
+
+	if node.Names != nil {
+		for _, name := range node.Names {
+			val, ok := module.Exports[name.Value]
+			if !ok {
+				return newTypedError(object.ImportError, "module %s has no export %s", module.Name, name.Value)
+			}
+
+			setVariable(env, name, val)
+		}
+
+		return nil
+	}
+
+	setVariable(env, node.Module, module)
+
+	return nil
+}
+
+// importModule returns the module imported by node, evaluating it in its own
+// environment unless an earlier import already did.
+func importModule(node *ast.ImportStatement, env *object.Environment) object.Object {
+	runtime := env.Runtime()
+
+	return module.Import(runtime, node.Path.Value, node.Token.Pos.Filename,
+		func(module *object.Module, program *ast.Program) *object.Error {
+			moduleEnv := object.NewModuleEnvironment(runtime)
+
+			err := ResolveModule(program)
+			if err == nil {
+				err, _ = Eval(program, moduleEnv).(*object.Error)
+			}
+
+			if err != nil {
+				err.AddFrame(object.Frame{Function: "<module " + module.Name + ">", Pos: node.Pos()})
+				return err
+			}
+
+			for _, stmt := range program.Statements {
+				if export, ok := stmt.(*ast.ExportStatement); ok {
+					for _, name := range export.Statement.Names() {
+						module.Exports[name.Value], _ = moduleEnv.Get(0, name.Binding.Slot)
+					}
+				}
+			}
+
+			return nil
+		})
+}
+
+// ResolveModule resolves the program of a module on its own, returning the
+// first error found.
+func ResolveModule(program *ast.Program) *object.Error {
+	if errs := resolver.New(IsBuiltin).Resolve(program); len(errs) != 0 {
+		return errs[0]
+	}
+
+	return nil
+}
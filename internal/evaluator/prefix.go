@@ -9,8 +9,11 @@ func evalPrefixExpression(operator string, right object.Object) object.Object {
 
 	case "-":
 		return evalMinusPrefixOperator(right)
+
+	case "~":
+		return evalBitNotPrefixOperator(right)
 	default:
-		return newError("unknown operator: %s%s", operator, right.Type())
+		return newTypedError(object.TypeError, "unknown operator: %s%s", operator, right.Type())
 	}
 }
 
@@ -42,8 +45,17 @@ func evalMinusPrefixOperator(right object.Object) object.Object {
 	}
 
 	if right.Type() != object.IntegerObj && right.Type() != object.FloatObj {
-		return newError("unknown operator: -%s", right.Type())
+		return newTypedError(object.TypeError, "unknown operator: -%s", right.Type())
 	}
 
 	return NULL
 }
+
+func evalBitNotPrefixOperator(right object.Object) object.Object {
+	integer, ok := right.(*object.Integer)
+	if !ok {
+		return newTypedError(object.TypeError, "unknown operator: ~%s", right.Type())
+	}
+
+	return &object.Integer{Value: ^integer.Value}
+}
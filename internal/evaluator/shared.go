@@ -0,0 +1,113 @@
+package evaluator
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/object"
+)
+
+// The functions below expose the semantics of the language to the bytecode
+// VM, so that both engines agree on what every operation does.
+
+// Infix applies a binary operator other than && and ||, which short-circuit.
+func Infix(operator string, left, right object.Object) object.Object {
+	return evalInfixExpression(operator, left, right)
+}
+
+// Prefix applies a unary operator.
+func Prefix(operator string, right object.Object) object.Object {
+	return evalPrefixExpression(operator, right)
+}
+
+func Index(left, index object.Object) object.Object {
+	return evalIndexExpression(left, index)
+}
+
+// SetIndex assigns to an element of an array or hash. It returns nil unless
+// the assignment fails.
+func SetIndex(left, index, val object.Object) object.Object {
+	return setIndex(left, index, val)
+}
+
+// Member reads the field name of obj, as in obj.name.
+func Member(obj object.Object, name string) object.Object {
+	return evalMember(obj, name)
+}
+
+// SetMember assigns to the field name of obj. It returns nil unless the
+// assignment fails.
+func SetMember(obj object.Object, name string, val object.Object) object.Object {
+	return setMember(obj, name, val)
+}
+
+// NewInstance creates an instance of a struct from a struct literal.
+func NewInstance(structObj object.Object, fields []string, values []object.Object) object.Object {
+	return newInstance(structObj, fields, values)
+}
+
+// NewClass creates a class from a class definition. The superclass is nil
+// when it extends nothing.
+func NewClass(name string, superclass object.Object, methods map[string]*object.Function) object.Object {
+	return newClass(name, superclass, methods)
+}
+
+// SuperMethod binds the method name of a superclass to self, as in
+// super.name.
+func SuperMethod(superclass, self object.Object, name string) object.Object {
+	return superMethod(superclass, self, name)
+}
+
+// FitsArray reports whether an array pattern of n elements, which has a rest
+// or not, matches val.
+func FitsArray(val object.Object, n int, rest bool) bool {
+	return fitsArray(val, n, rest)
+}
+
+// UnpackArray returns the elements an array pattern of n elements binds,
+// followed by the remaining ones when it has a rest.
+func UnpackArray(rt *object.Runtime, val object.Object, n int, rest bool) ([]object.Object, *object.Error) {
+	return unpackArray(rt, val, n, rest)
+}
+
+// UnpackField returns the value a hash pattern binds to the key name.
+func UnpackField(val object.Object, name string) object.Object {
+	return unpackField(val, name)
+}
+
+// NoMatch returns the error raised when no arm of a match expression matches
+// val.
+func NoMatch(val object.Object) *object.Error {
+	return noMatch(val)
+}
+
+func IsTruthy(obj object.Object) bool {
+	return isTruthy(obj)
+}
+
+// Throw returns the error raised by a throw statement throwing val.
+func Throw(val object.Object) *object.Error {
+	return throwValue(val)
+}
+
+// LookupBuiltin returns the builtin function called name.
+func LookupBuiltin(name string) (*object.Builtin, bool) {
+	builtin, ok := builtins[name]
+	return builtin, ok
+}
+
+// Call calls a function from outside of any program, giving builtins the
+// runtime rt.
+func Call(rt *object.Runtime, fn object.Object, args ...object.Object) object.Object {
+	return applyFunction(rt, fn, args, nil)
+}
+
+// IsBuiltin reports whether name is a builtin function, for the resolver.
+func IsBuiltin(name string) bool {
+	_, ok := builtins[name]
+	return ok
+}
+
+// CalleeName returns the name the function of a call was referred to by, as
+// shown in stack traces.
+func CalleeName(call ast.Node) string {
+	return calleeName(call)
+}
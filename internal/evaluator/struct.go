@@ -0,0 +1,108 @@
+package evaluator
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/object"
+)
+
+func evalStructDefinition(node *ast.StructDefinition) object.Object {
+	fields := make([]string, len(node.Fields))
+	for i, field := range node.Fields {
+		fields[i] = field.Value
+	}
+
+	return object.NewStructType(node.Name.Value, fields)
+}
+
+func evalStructLiteral(node *ast.StructLiteral, env *object.Environment) object.Object {
+	structObj := Eval(node.Struct, env)
+	if isError(structObj) {
+		return structObj
+	}
+
+	values := evalExpressions(node.Values, env)
+	if len(values) == 1 && isError(values[0]) {
+		return values[0]
+	}
+
+	fields := make([]string, len(node.Fields))
+	for i, field := range node.Fields {
+		fields[i] = field.Value
+	}
+
+	return newInstance(structObj, fields, values)
+}
+
+// newInstance creates an instance of a struct with the given fields, the
+// others being null.
+func newInstance(structObj object.Object, fields []string, values []object.Object) object.Object {
+	st, ok := structObj.(*object.StructType)
+	if !ok {
+		return newTypedError(object.TypeError, "not a struct: %s", structObj.Type())
+	}
+
+	instance := &object.Instance{Struct: st, Fields: make([]object.Object, len(st.Fields))}
+	for i := range instance.Fields {
+		instance.Fields[i] = NULL
+	}
+
+	for i, field := range fields {
+		index, ok := st.FieldIndex(field)
+		if !ok {
+			return newTypedError(object.TypeError, "%s has no field %s", st.Name, field)
+		}
+
+		instance.Fields[index] = values[i]
+	}
+
+	return instance
+}
+
+func evalMemberAssignment(
+	as *ast.AssignStatement,
+	target *ast.MemberExpression,
+	env *object.Environment,
+) object.Object {
+	obj := Eval(target.Object, env)
+	if isError(obj) {
+		return obj
+	}
+
+	var current object.Object
+	if as.Operator != "=" {
+		current = evalMember(obj, target.Property.Value)
+		if isError(current) {
+			return current
+		}
+	}
+
+	val := evalAssignedValue(as, current, env)
+	if isError(val) {
+		return val
+	}
+
+	return setMember(obj, target.Property.Value, val)
+}
+
+// setMember assigns to the field name of obj, which must be an instance.
+// Assigning to a new field of an instance of a class creates it.
+func setMember(obj object.Object, name string, val object.Object) object.Object {
+	if instance, ok := obj.(*object.ClassInstance); ok {
+		instance.Fields.Set(&object.String{Value: name}, val)
+		return nil
+	}
+
+	instance, ok := obj.(*object.Instance)
+	if !ok {
+		return newTypedError(object.TypeError, "cannot assign to field %s of %s", name, obj.Type())
+	}
+
+	index, ok := instance.Struct.FieldIndex(name)
+	if !ok {
+		return newTypedError(object.TypeError, "%s has no field %s", instance.Struct.Name, name)
+	}
+
+	instance.Fields[index] = val
+
+	return nil
+}
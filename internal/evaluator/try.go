@@ -0,0 +1,58 @@
+package evaluator
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/object"
+)
+
+func evalThrowStatement(node *ast.ThrowStatement, env *object.Environment) object.Object {
+	val := Eval(node.Value, env)
+	if isError(val) {
+		return val
+	}
+
+	return throwValue(val)
+}
+
+// throwValue returns the error raised by throwing val.
+func throwValue(val object.Object) *object.Error {
+	switch val := val.(type) {
+	case *object.ErrorValue:
+		// throwing a caught error again keeps where it originally happened
+		return val.Error
+	case *object.String:
+		return newError("%s", val.Value)
+	default:
+		return newError("%s", val.Inspect())
+	}
+}
+
+func evalTryStatement(node *ast.TryStatement, env *object.Environment) object.Object {
+	result := Eval(node.Block, env)
+
+	if err, ok := result.(*object.Error); ok && !err.Fatal && node.Catch != nil {
+		catchEnv := object.NewEnclosedEnvironment(env)
+		setVariable(catchEnv, node.CatchParam, &object.ErrorValue{Error: err})
+
+		result = Eval(node.Catch, catchEnv)
+	}
+
+	// exceeding the limits of the runtime stops the program at once, without
+	// running finally blocks
+	if err, ok := result.(*object.Error); ok && err.Fatal {
+		return err
+	}
+
+	if node.Finally != nil {
+		// errors and control flow in the finally block replace the outcome of
+		// the rest of the statement
+		finally := Eval(node.Finally, env)
+
+		switch finally.(type) {
+		case *object.Error, *object.ReturnValue, *object.Break, *object.Continue:
+			return finally
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,38 @@
+package evaluator
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/object"
+)
+
+func evalWhileStatement(ws *ast.WhileStatement, env *object.Environment) object.Object {
+	var result object.Object = NULL
+
+	for {
+		condition := Eval(ws.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+
+		if !isTruthy(condition) {
+			break
+		}
+
+		evaluated := Eval(ws.Body, object.NewEnclosedEnvironment(env))
+
+		if evaluated != nil {
+			switch evaluated.Type() {
+			case object.ErrorObj, object.ReturnValueObj:
+				return evaluated
+			case object.BreakObj:
+				return result
+			case object.ContinueObj:
+				continue
+			}
+		}
+
+		result = evaluated
+	}
+
+	return result
+}
@@ -1,25 +1,43 @@
 package lexer
 
 import (
+	"sunbird/internal/diagnostic"
 	"sunbird/internal/token"
 )
 
 type Lexer struct {
+	filename     string
 	input        string
 	position     int  // Current position in input
 	readPosition int  // Current reading position
 	ch           byte // Current char under examination
-	line         int  // Current line number
-	col          int  // Current column number
+	line         int  // Line number of ch, starting at 1
+	col          int  // Column number of ch, starting at 1
+
+	errors         []diagnostic.Diagnostic
+	interpolations []interpolation // strings with an open ${...} expression
 }
 
-func New(input string) *Lexer {
-	l := &Lexer{input: input, line: 0, col: 0}
+// New creates a lexer for input. The filename is only used in the positions
+// of the tokens and can be empty.
+func New(filename, input string) *Lexer {
+	l := &Lexer{filename: filename, input: input, line: 1, col: 0}
 	l.readChar()
 	return l
 }
 
 func (l *Lexer) readChar() {
+	if l.readPosition > len(l.input) {
+		return // already at the end of the input
+	}
+
+	if l.ch == '\n' {
+		l.line++
+		l.col = 1
+	} else {
+		l.col++
+	}
+
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
 	} else {
@@ -28,15 +46,37 @@ func (l *Lexer) readChar() {
 
 	l.position = l.readPosition
 	l.readPosition += 1
+}
 
-	if l.ch == '\n' {
-		l.line++
-		l.col = 0
-	} else {
-		l.col++
+// currentPosition returns the position of the char under examination.
+func (l *Lexer) currentPosition() token.Position {
+	return token.Position{
+		Filename: l.filename,
+		Offset:   l.position,
+		Line:     l.line,
+		Col:      l.col,
 	}
 }
 
+// nextPosition returns the position immediately after the char under
+// examination.
+func (l *Lexer) nextPosition() token.Position {
+	pos := l.currentPosition()
+
+	switch {
+	case l.atEOF():
+	case l.ch == '\n':
+		pos.Offset++
+		pos.Line++
+		pos.Col = 1
+	default:
+		pos.Offset++
+		pos.Col++
+	}
+
+	return pos
+}
+
 func (l *Lexer) readIdentifier() string {
 	position := l.position
 	for isLetter(l.ch) {
@@ -45,18 +85,24 @@ func (l *Lexer) readIdentifier() string {
 	return l.input[position:l.position]
 }
 
-func (l *Lexer) readString() string {
-	startingQuote := l.ch
-
-	l.readChar() // skip the starting quote
+// Errors returns the messages of the errors found so far.
+func (l *Lexer) Errors() []string {
+	return diagnostic.Messages(l.errors)
+}
 
-	position := l.position
+func (l *Lexer) Diagnostics() []diagnostic.Diagnostic {
+	return l.errors
+}
 
-	for l.ch != startingQuote {
-		l.readChar()
-	}
+// error reports an error spanning from start up to and including the char
+// under examination.
+func (l *Lexer) error(start token.Position, code diagnostic.Code, format string, a ...interface{}) {
+	span := diagnostic.Span{Start: start, End: l.nextPosition()}
+	l.errors = append(l.errors, diagnostic.New(code, span, format, a...))
+}
 
-	return l.input[position:l.position]
+func (l *Lexer) atEOF() bool {
+	return l.position >= len(l.input)
 }
 
 func (l *Lexer) readNumber() (string, token.TokenType) {
@@ -78,34 +124,45 @@ func (l *Lexer) readNumber() (string, token.TokenType) {
 }
 
 func (l *Lexer) NextToken() token.Token {
-	pos := token.Position{
-		Filename: "",
-		Offset:   l.position,
-		Line:     l.line,
-		Col:      l.col - 1,
-	}
+	l.skipWhitespace()
+
+	pos := l.currentPosition()
 
 	tok := token.Token{
 		Pos: pos,
 	}
 
-	l.skipWhitespace()
-
 	switch l.ch {
 	case '=':
 		if l.peekChar() == '=' {
 			ch := l.ch
 			l.readChar()
 			tok = token.Token{Type: token.Eq, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '>' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.Arrow, Literal: string(ch) + string(l.ch)}
 		} else {
 			tok = newToken(token.Assign, l.ch, pos)
 		}
 
 	case '+':
-		tok = newToken(token.Plus, l.ch, pos)
+		if l.peekChar() == '=' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.PlusAssign, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.Plus, l.ch, pos)
+		}
 
 	case '-':
-		tok = newToken(token.Minus, l.ch, pos)
+		if l.peekChar() == '=' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.MinusAssign, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.Minus, l.ch, pos)
+		}
 
 	case ';':
 		tok = newToken(token.Semicolon, l.ch, pos)
@@ -130,24 +187,69 @@ func (l *Lexer) NextToken() token.Token {
 			l.readChar() // skip the /
 			l.readChar() // skip the *
 
-			for l.ch != '*' && l.peekChar() != '/' {
+			for l.ch != '*' || l.peekChar() != '/' {
+				if l.atEOF() {
+					l.error(pos, diagnostic.UnterminatedComment, "unterminated block comment")
+					return l.NextToken()
+				}
+
 				l.readChar()
 			}
 
 			l.readChar() // skip the *
 			l.readChar() // skip the /
 			return l.NextToken()
+		} else if l.peekChar() == '=' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.SlashAssign, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.Slash, l.ch, pos)
 		}
-		tok = newToken(token.Slash, l.ch, pos)
 
 	case '*':
-		tok = newToken(token.Asterisk, l.ch, pos)
+		if l.peekChar() == '*' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.Power, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '=' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.AsteriskAssign, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.Asterisk, l.ch, pos)
+		}
+
+	case '%':
+		if l.peekChar() == '=' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.PercentAssign, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.Percent, l.ch, pos)
+		}
+
+	case '~':
+		if l.peekChar() == '/' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.FloorDiv, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.BitNot, l.ch, pos)
+		}
+
+	case '^':
+		tok = newToken(token.BitXor, l.ch, pos)
 
 	case '<':
 		if l.peekChar() == '=' {
 			ch := l.ch
 			l.readChar()
 			tok = token.Token{Type: token.LE, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '<' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.ShiftLeft, Literal: string(ch) + string(l.ch)}
 		} else {
 			tok = newToken(token.LT, l.ch, pos)
 		}
@@ -157,6 +259,10 @@ func (l *Lexer) NextToken() token.Token {
 			ch := l.ch
 			l.readChar()
 			tok = token.Token{Type: token.GE, Literal: string(ch) + string(l.ch)}
+		} else if l.peekChar() == '>' {
+			ch := l.ch
+			l.readChar()
+			tok = token.Token{Type: token.ShiftRight, Literal: string(ch) + string(l.ch)}
 		} else {
 			tok = newToken(token.GT, l.ch, pos)
 		}
@@ -171,6 +277,8 @@ func (l *Lexer) NextToken() token.Token {
 			l.readChar()
 
 			tok = token.Token{Type: token.Pipe, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.BitOr, l.ch, pos)
 		}
 
 	case '&':
@@ -178,6 +286,8 @@ func (l *Lexer) NextToken() token.Token {
 			ch := l.ch
 			l.readChar()
 			tok = token.Token{Type: token.And, Literal: string(ch) + string(l.ch)}
+		} else {
+			tok = newToken(token.BitAnd, l.ch, pos)
 		}
 
 	case '(':
@@ -189,10 +299,36 @@ func (l *Lexer) NextToken() token.Token {
 	case ',':
 		tok = newToken(token.Comma, l.ch, pos)
 
+	case ':':
+		tok = newToken(token.Colon, l.ch, pos)
+
+	case '.':
+		if l.peekChar() == '.' && l.readPosition+1 < len(l.input) && l.input[l.readPosition+1] == '.' {
+			l.readChar()
+			l.readChar()
+			tok = token.Token{Type: token.Ellipsis, Literal: "..."}
+		} else {
+			tok = newToken(token.Dot, l.ch, pos)
+		}
+
 	case '{':
+		if n := len(l.interpolations); n > 0 {
+			l.interpolations[n-1].depth++
+		}
+
 		tok = newToken(token.LBrace, l.ch, pos)
 
 	case '}':
+		if n := len(l.interpolations); n > 0 {
+			if l.interpolations[n-1].depth == 0 {
+				// the brace closes an interpolated expression, so the string continues
+				tok = l.readStringPart(tok, l.interpolations[n-1].quote, true)
+				break
+			}
+
+			l.interpolations[n-1].depth--
+		}
+
 		tok = newToken(token.RBrace, l.ch, pos)
 
 	case '[':
@@ -202,18 +338,37 @@ func (l *Lexer) NextToken() token.Token {
 		tok = newToken(token.RBracket, l.ch, pos)
 
 	case '"', '\'':
+		tok = l.readStringPart(tok, l.ch, false)
+
+	case '`':
+		literal, ok := l.readRawString(pos)
+
 		tok.Type = token.String
-		tok.Literal = l.readString()
+		tok.Literal = literal
+
+		if !ok {
+			tok.Type = token.Illegal
+		}
 
 	case 0:
+		if len(l.interpolations) > 0 {
+			start := l.interpolations[0].start
+			l.error(start, diagnostic.UnterminatedString, "unterminated string interpolation")
+			l.interpolations = nil
+		}
+
 		tok.Literal = ""
 		tok.Type = token.EOF
+		tok.End = pos
+
+		return tok
 
 	default:
 		switch {
 		case isLetter(l.ch):
 			tok.Literal = l.readIdentifier()
 			tok.Type = token.LookupIdent(tok.Literal)
+			tok.End = l.currentPosition()
 			return tok // Return earlier because readChar() is already being executed in LookupIdent()
 
 		case isDigit(l.ch):
@@ -221,14 +376,22 @@ func (l *Lexer) NextToken() token.Token {
 
 			tok.Literal = literal
 			tok.Type = tokenType
+			tok.End = l.currentPosition()
 			return tok // Return earlier because readChar() is already being executed in readNumber ()
 
 		default:
+			l.error(pos, diagnostic.IllegalCharacter, "illegal character: %q", l.ch)
 			tok = newToken(token.Illegal, l.ch, pos)
 		}
 	}
 
 	l.readChar()
+
+	// Two character tokens are built without a position, so it's set here for
+	// every token
+	tok.Pos = pos
+	tok.End = l.currentPosition()
+
 	return tok
 }
 
@@ -244,6 +407,10 @@ func isDigit(ch byte) bool {
 	return '0' <= ch && ch <= '9'
 }
 
+func isHexDigit(ch byte) bool {
+	return isDigit(ch) || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
+}
+
 func (l *Lexer) skipWhitespace() {
 	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
 		l.readChar()
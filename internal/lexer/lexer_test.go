@@ -35,6 +35,10 @@ if (5 < 10) {
 [1, 2];
 ||
 &&
+{"foo": "bar"}
+while break continue
+for x in xs
+try catch finally throw e.message
 `
 
 	tests := []struct {
@@ -130,9 +134,28 @@ if (5 < 10) {
 		{token.Semicolon, ";"},
 		{token.Or, "||"},
 		{token.And, "&&"},
+		{token.LBrace, "{"},
+		{token.String, "foo"},
+		{token.Colon, ":"},
+		{token.String, "bar"},
+		{token.RBrace, "}"},
+		{token.While, "while"},
+		{token.Break, "break"},
+		{token.Continue, "continue"},
+		{token.For, "for"},
+		{token.Ident, "x"},
+		{token.In, "in"},
+		{token.Ident, "xs"},
+		{token.Try, "try"},
+		{token.Catch, "catch"},
+		{token.Finally, "finally"},
+		{token.Throw, "throw"},
+		{token.Ident, "e"},
+		{token.Dot, "."},
+		{token.Ident, "message"},
 		{token.EOF, ""},
 	}
-	l := lexer.New(input)
+	l := lexer.New("", input)
 
 	for i, tt := range tests {
 		tok := l.NextToken()
@@ -156,3 +179,227 @@ if (5 < 10) {
 		}
 	}
 }
+
+func TestStringEscapes(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{`"a\nb"`, token.String, "a\nb"},
+		{`"tab\there"`, token.String, "tab\there"},
+		{`"carriage\r"`, token.String, "carriage\r"},
+		{`"back\\slash"`, token.String, `back\slash`},
+		{`"say \"hi\""`, token.String, `say "hi"`},
+		{`'it\'s'`, token.String, "it's"},
+		{`"\u{48}\u{49}"`, token.String, "HI"},
+		{`"\u{1F426}"`, token.String, "\U0001F426"},
+		{`"héllo"`, token.String, "héllo"},
+		{"`raw\\n\\t`", token.String, `raw\n\t`},
+		{"`line one\nline two`", token.String, "line one\nline two"},
+		{"\"multi\nline\"", token.String, "multi\nline"},
+		{`"unterminated`, token.Illegal, "unterminated"},
+		{"`unterminated", token.Illegal, "unterminated"},
+		{`"bad \q escape"`, token.Illegal, "bad  escape"},
+		{`"\u{110000}"`, token.Illegal, ""},
+	}
+
+	for i, tt := range tests {
+		l := lexer.New("", tt.input)
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+
+		if next := l.NextToken(); next.Type != token.EOF {
+			t.Fatalf("tests[%d] - expected EOF after string. got=%q", i, next.Type)
+		}
+	}
+}
+
+func TestLexerErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{`"never closed`, "unterminated string"},
+		{"`never closed", "unterminated raw string"},
+		{`"\q"`, `unknown escape sequence: \q`},
+		{`"\u41"`, `invalid unicode escape: expected '{' after \u`},
+		{`"\u{41"`, `invalid unicode escape: expected '}' after \u{41`},
+		{`"\u{D800}"`, `invalid unicode escape: \u{D800}`},
+		{`"\u{1234567}"`, `invalid unicode escape: \u{1234567}`},
+		{"@", `illegal character: '@'`},
+		{`"${x`, "unterminated string interpolation"},
+		{`"${ {} `, "unterminated string interpolation"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New("", tt.input)
+		for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		}
+
+		errors := l.Errors()
+		if len(errors) != 1 {
+			t.Errorf("%s: expected 1 error. got=%q", tt.input, errors)
+			continue
+		}
+
+		if errors[0] != tt.expectedError {
+			t.Errorf("%s: wrong error. expected=%q, got=%q", tt.input, tt.expectedError, errors[0])
+		}
+	}
+}
+
+func TestStringInterpolation(t *testing.T) {
+	input := `"Hello ${name}, you have ${len(items)} items" "${a + "${b}"}" 'it\'s ${x}' "\${no}" "{}$"`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.InterpStart, "Hello "},
+		{token.Ident, "name"},
+		{token.InterpMid, ", you have "},
+		{token.Ident, "len"},
+		{token.LParen, "("},
+		{token.Ident, "items"},
+		{token.RParen, ")"},
+		{token.InterpEnd, " items"},
+		{token.InterpStart, ""},
+		{token.Ident, "a"},
+		{token.Plus, "+"},
+		{token.InterpStart, ""},
+		{token.Ident, "b"},
+		{token.InterpEnd, ""},
+		{token.InterpEnd, ""},
+		{token.InterpStart, "it's "},
+		{token.Ident, "x"},
+		{token.InterpEnd, ""},
+		{token.String, "${no}"},
+		{token.String, "{}$"},
+		{token.EOF, ""},
+	}
+
+	l := lexer.New("", input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestArithmeticAndBitwiseOperators(t *testing.T) {
+	input := `% ** ~/ ~ & | ^ << >> <= >= && || |> * / += -= *= /= %= => ... .. // comment`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.Percent, "%"},
+		{token.Power, "**"},
+		{token.FloorDiv, "~/"},
+		{token.BitNot, "~"},
+		{token.BitAnd, "&"},
+		{token.BitOr, "|"},
+		{token.BitXor, "^"},
+		{token.ShiftLeft, "<<"},
+		{token.ShiftRight, ">>"},
+		{token.LE, "<="},
+		{token.GE, ">="},
+		{token.And, "&&"},
+		{token.Or, "||"},
+		{token.Pipe, "|>"},
+		{token.Asterisk, "*"},
+		{token.Slash, "/"},
+		{token.PlusAssign, "+="},
+		{token.MinusAssign, "-="},
+		{token.AsteriskAssign, "*="},
+		{token.SlashAssign, "/="},
+		{token.PercentAssign, "%="},
+		{token.Arrow, "=>"},
+		{token.Ellipsis, "..."},
+		{token.Dot, "."},
+		{token.Dot, "."},
+		{token.EOF, ""},
+	}
+
+	l := lexer.New("", input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestTokenPositions(t *testing.T) {
+	input := "var name = \"hi\";\n  x <= 10.5\n`raw\nstring` ~/"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		line, col       int
+		endLine, endCol int
+	}{
+		{token.Var, 1, 1, 1, 4},
+		{token.Ident, 1, 5, 1, 9},
+		{token.Assign, 1, 10, 1, 11},
+		{token.String, 1, 12, 1, 16},
+		{token.Semicolon, 1, 16, 1, 17},
+		{token.Ident, 2, 3, 2, 4},
+		{token.LE, 2, 5, 2, 7},
+		{token.Float, 2, 8, 2, 12},
+		{token.String, 3, 1, 4, 8},
+		{token.FloorDiv, 4, 9, 4, 11},
+		{token.EOF, 4, 11, 4, 11},
+	}
+
+	l := lexer.New("main.sb", input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Pos.Filename != "main.sb" {
+			t.Errorf("tests[%d] - filename wrong. got=%q", i, tok.Pos.Filename)
+		}
+
+		if tok.Pos.Line != tt.line || tok.Pos.Col != tt.col {
+			t.Errorf("tests[%d] - pos wrong. expected=%d:%d, got=%d:%d",
+				i, tt.line, tt.col, tok.Pos.Line, tok.Pos.Col)
+		}
+
+		if tok.End.Line != tt.endLine || tok.End.Col != tt.endCol {
+			t.Errorf("tests[%d] - end wrong. expected=%d:%d, got=%d:%d",
+				i, tt.endLine, tt.endCol, tok.End.Line, tok.End.Col)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package lexer
+
+import (
+	"strconv"
+	"strings"
+	"sunbird/internal/diagnostic"
+	"sunbird/internal/token"
+	"unicode/utf8"
+)
+
+// interpolation tracks a string whose ${...} expression is being lexed.
+type interpolation struct {
+	quote byte           // the quote that terminates the string
+	depth int            // number of unclosed braces inside the expression
+	start token.Position // position of the opening quote
+}
+
+// readStringPart lexes the string segment following an opening quote, or
+// following the closing brace of an interpolated expression when continuing.
+// Strings without interpolation become a single String token, interpolated
+// ones are split into InterpStart, InterpMid and InterpEnd tokens around the
+// tokens of the embedded expressions.
+func (l *Lexer) readStringPart(tok token.Token, quote byte, continuing bool) token.Token {
+	start := tok.Pos
+	if continuing {
+		start = l.interpolations[len(l.interpolations)-1].start
+	}
+
+	segment, interpolated, valid := l.readStringSegment(quote, start)
+
+	tok.Literal = segment
+
+	switch {
+	case interpolated && !continuing:
+		l.interpolations = append(l.interpolations, interpolation{quote: quote, start: start})
+		tok.Type = token.InterpStart
+	case interpolated:
+		tok.Type = token.InterpMid
+	case continuing:
+		l.interpolations = l.interpolations[:len(l.interpolations)-1]
+		tok.Type = token.InterpEnd
+	default:
+		tok.Type = token.String
+	}
+
+	if !valid {
+		tok.Type = token.Illegal
+	}
+
+	return tok
+}
+
+// readStringSegment reads string content up to the closing quote or the start
+// of an interpolated ${...} expression, decoding escape sequences. It reports
+// whether the segment ends at an interpolation and whether it is valid, an
+// unterminated string or an invalid escape make it invalid.
+func (l *Lexer) readStringSegment(quote byte, start token.Position) (string, bool, bool) {
+	valid := true
+
+	var out strings.Builder
+
+	l.readChar() // skip the quote or the closing brace
+
+	for l.ch != quote {
+		if l.atEOF() {
+			l.error(start, diagnostic.UnterminatedString, "unterminated string")
+			return out.String(), false, false
+		}
+
+		switch {
+		case l.ch == '\\':
+			escape := l.currentPosition()
+			l.readChar() // skip the backslash
+
+			if !l.readEscape(&out, escape) {
+				valid = false
+			}
+
+		case l.ch == '$' && l.peekChar() == '{':
+			l.readChar() // move onto the {
+			return out.String(), true, valid
+
+		default:
+			out.WriteByte(l.ch)
+		}
+
+		l.readChar()
+	}
+
+	return out.String(), false, valid
+}
+
+func (l *Lexer) readEscape(out *strings.Builder, start token.Position) bool {
+	switch l.ch {
+	case 'n':
+		out.WriteByte('\n')
+	case 't':
+		out.WriteByte('\t')
+	case 'r':
+		out.WriteByte('\r')
+	case '0':
+		out.WriteByte(0)
+	case '\\', '"', '\'', '`', '$':
+		out.WriteByte(l.ch)
+	case 'u':
+		return l.readUnicodeEscape(out, start)
+	default:
+		if l.atEOF() {
+			// the unterminated string gets reported by readString
+			return true
+		}
+
+		l.error(start, diagnostic.InvalidEscape, "unknown escape sequence: \\%c", l.ch)
+		return false
+	}
+
+	return true
+}
+
+// readUnicodeEscape decodes a \u{...} escape holding 1 to 6 hex digits.
+func (l *Lexer) readUnicodeEscape(out *strings.Builder, start token.Position) bool {
+	if l.peekChar() != '{' {
+		l.error(start, diagnostic.InvalidEscape, "invalid unicode escape: expected '{' after \\u")
+		return false
+	}
+
+	l.readChar() // skip the u
+
+	position := l.position + 1
+	for l.peekChar() != '}' && !l.atEOF() && isHexDigit(l.peekChar()) {
+		l.readChar()
+	}
+
+	digits := l.input[position : l.position+1]
+
+	if l.peekChar() != '}' {
+		l.error(start, diagnostic.InvalidEscape, "invalid unicode escape: expected '}' after \\u{%s", digits)
+		return false
+	}
+
+	l.readChar() // move onto the }
+
+	code, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil || len(digits) > 6 || !utf8.ValidRune(rune(code)) {
+		l.error(start, diagnostic.InvalidEscape, "invalid unicode escape: \\u{%s}", digits)
+		return false
+	}
+
+	out.WriteRune(rune(code))
+
+	return true
+}
+
+// readRawString reads a backtick string. Raw strings can span multiple lines
+// and don't process escape sequences.
+func (l *Lexer) readRawString(start token.Position) (string, bool) {
+	l.readChar() // skip the starting backtick
+
+	position := l.position
+
+	for l.ch != '`' {
+		if l.atEOF() {
+			l.error(start, diagnostic.UnterminatedString, "unterminated raw string")
+			return l.input[position:l.position], false
+		}
+
+		l.readChar()
+	}
+
+	return l.input[position:l.position], true
+}
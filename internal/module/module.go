@@ -0,0 +1,136 @@
+// Package module finds, parses and caches the modules programs import, for
+// both engines, which only differ in how they evaluate them.
+package module
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sunbird/internal/ast"
+	"sunbird/internal/lexer"
+	"sunbird/internal/object"
+	"sunbird/internal/parser"
+)
+
+// Extension is added to imported paths without an extension.
+const Extension = ".sb"
+
+// Loader evaluates the program of a module and fills in its exports.
+type Loader func(module *object.Module, program *ast.Program) *object.Error
+
+// Import returns the module imported as path by the file importer, in the
+// runtime r. The module is parsed and handed to load the first time it is
+// imported, later imports reuse the result.
+func Import(r *object.Runtime, path, importer string, load Loader) object.Object {
+	resolved, ok := Resolve(r.SearchPath, path, importer)
+	if !ok {
+		return importError("cannot find module %q", path)
+	}
+
+	for i, importing := range r.Importing {
+		if importing == resolved {
+			cycle := []string{}
+			for _, p := range append(r.Importing[i:], resolved) {
+				cycle = append(cycle, filepath.Base(p))
+			}
+
+			return importError("import cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	if module, ok := r.Modules[resolved]; ok {
+		return module
+	}
+
+	src, err := os.ReadFile(resolved)
+	if err != nil {
+		return importError("cannot read module %q: %s", path, err)
+	}
+
+	p := parser.New(lexer.New(resolved, string(src)))
+	program := p.ParseProgram()
+
+	if diagnostics := p.Diagnostics(); len(diagnostics) != 0 {
+		err := importError("syntax error in module %q: %s", path, diagnostics[0].Error())
+		if len(diagnostics) > 1 {
+			err.Message += " (and more)"
+		}
+
+		return err
+	}
+
+	module := &object.Module{
+		Name:    strings.TrimSuffix(filepath.Base(resolved), filepath.Ext(resolved)),
+		Path:    resolved,
+		Exports: map[string]object.Object{},
+	}
+
+	r.Importing = append(r.Importing, resolved)
+	defer func() { r.Importing = r.Importing[:len(r.Importing)-1] }()
+
+	if err := load(module, program); err != nil {
+		return err
+	}
+
+	r.Modules[resolved] = module
+
+	return module
+}
+
+// Resolve finds the file imported as path by the file importer. Paths
+// starting with ./ or ../ are relative to the importing file, other relative
+// paths are also looked up in the directories of searchPath.
+func Resolve(searchPath []string, path, importer string) (string, bool) {
+	if filepath.Ext(path) == "" {
+		path += Extension
+	}
+
+	dir := "."
+	if importer != "" {
+		dir = filepath.Dir(importer)
+	}
+
+	var candidates []string
+
+	switch {
+	case filepath.IsAbs(path):
+		candidates = []string{path}
+
+	case strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../"):
+		candidates = []string{filepath.Join(dir, path)}
+
+	default:
+		candidates = []string{filepath.Join(dir, path)}
+		for _, searchDir := range searchPath {
+			candidates = append(candidates, filepath.Join(searchDir, path))
+		}
+	}
+
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			abs, err := filepath.Abs(candidate)
+			if err != nil {
+				return "", false
+			}
+
+			return abs, true
+		}
+	}
+
+	return "", false
+}
+
+// Name returns the name an import of path binds the module to when it has no
+// alias.
+func Name(path string) string {
+	if filepath.Ext(path) == "" {
+		path += Extension
+	}
+
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+func importError(format string, a ...interface{}) *object.Error {
+	return &object.Error{Message: fmt.Sprintf(format, a...), Kind: object.ImportError}
+}
@@ -0,0 +1,59 @@
+package object
+
+// Class is a class declared by a program, which is called to create
+// instances.
+type Class struct {
+	Name       string
+	Superclass *Class // nil for a class that extends nothing
+	Methods    map[string]*Function
+}
+
+func (c *Class) Type() ObjectType { return ClassObj }
+func (c *Class) Inspect() string  { return "<class " + c.Name + ">" }
+
+// Method finds a method of the class or of its superclasses.
+func (c *Class) Method(name string) (*Function, bool) {
+	for class := c; class != nil; class = class.Superclass {
+		if method, ok := class.Methods[name]; ok {
+			return method, true
+		}
+	}
+
+	return nil, false
+}
+
+// IsSubclassOf reports whether c is other or inherits from it.
+func (c *Class) IsSubclassOf(other *Class) bool {
+	for class := c; class != nil; class = class.Superclass {
+		if class == other {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClassInstance is an instance of a class. Unlike the instances of structs,
+// its fields are created by assigning to them.
+type ClassInstance struct {
+	Class  *Class
+	Fields *Hash
+}
+
+func NewClassInstance(class *Class) *ClassInstance {
+	return &ClassInstance{Class: class, Fields: NewHash()}
+}
+
+func (o *ClassInstance) Type() ObjectType { return InstanceObj }
+func (o *ClassInstance) Inspect() string  { return inspect(o, nil) }
+
+// BoundMethod is a method read from an instance, which it is called with as
+// self.
+type BoundMethod struct {
+	Self   Object
+	Method *Function
+	Name   string
+}
+
+func (bm *BoundMethod) Type() ObjectType { return BoundMethodObj }
+func (bm *BoundMethod) Inspect() string  { return "<method " + bm.Name + ">" }
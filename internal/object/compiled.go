@@ -0,0 +1,68 @@
+package object
+
+import (
+	"sort"
+	"sunbird/internal/ast"
+	"sunbird/internal/code"
+)
+
+// CompiledFunction is a function literal, or a whole program, lowered to
+// bytecode. The VM turns it into a Function when it creates a closure.
+type CompiledFunction struct {
+	Instructions  code.Instructions
+	Constants     []Object // the constant pool the instructions refer to
+	NumLocals     int      // including the parameters
+	NumCells      int
+	NumParameters int
+	Captures      []Capture
+	Locations     []Location
+
+	Parameters []ast.Pattern
+	Body       *ast.BlockStatement
+}
+
+func (cf *CompiledFunction) Type() ObjectType { return CompiledFunctionObj }
+func (cf *CompiledFunction) Inspect() string  { return "<compiled function>" }
+
+// NodeAt returns the node the instruction at offset was compiled from.
+func (cf *CompiledFunction) NodeAt(offset int) ast.Node {
+	i := sort.Search(len(cf.Locations), func(i int) bool {
+		return cf.Locations[i].Offset > offset
+	})
+
+	if i == 0 {
+		return nil
+	}
+
+	return cf.Locations[i-1].Node
+}
+
+// Capture tells where a closure finds one of its free variables when it is
+// created: in a cell of the enclosing function, or in one of its free
+// variables.
+type Capture struct {
+	Free  bool
+	Index int
+}
+
+// Location maps the instructions starting at Offset, up to the next
+// location, to the node they were compiled from.
+type Location struct {
+	Offset int
+	Node   ast.Node
+}
+
+// Cell holds a variable shared between a function and the closures it
+// creates.
+type Cell struct {
+	Value Object
+}
+
+// IteratorValue is the iterator of a for-in loop run by the VM.
+type IteratorValue struct {
+	Iterator Iterator
+	KeysOnly bool // a single loop variable walks the keys of a hash
+}
+
+func (iv *IteratorValue) Type() ObjectType { return IteratorObj }
+func (iv *IteratorValue) Inspect() string  { return "<iterator>" }
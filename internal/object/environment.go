@@ -1,42 +1,59 @@
 package object
 
 func NewEnvironment() *Environment {
-	s := make(map[string]Object)
-	return &Environment{store: s}
+	return NewModuleEnvironment(NewRuntime())
 }
 
+// NewModuleEnvironment creates the top level environment of a module, sharing
+// the runtime of the program importing it.
+func NewModuleEnvironment(runtime *Runtime) *Environment {
+	return &Environment{runtime: runtime}
+}
+
+// Environment holds the variables of a scope in slots, numbered by the
+// resolver. A nil slot is a variable that was not declared yet.
 type Environment struct {
-	store map[string]Object
-	outer *Environment
+	slots   []Object
+	outer   *Environment
+	runtime *Runtime
+}
+
+func (e *Environment) Runtime() *Runtime {
+	return e.runtime
 }
 
-func (e *Environment) Get(name string) (Object, bool) {
-	obj, ok := e.store[name]
-	if !ok && e.outer != nil {
-		obj, ok = e.outer.Get(name)
+// Get returns the variable in slot of the environment depth levels out. The
+// result is false when the variable was not declared yet.
+func (e *Environment) Get(depth, slot int) (Object, bool) {
+	env := e.ancestor(depth)
+	if slot >= len(env.slots) || env.slots[slot] == nil {
+		return nil, false
 	}
 
-	return obj, ok
+	return env.slots[slot], true
 }
 
-func (e *Environment) Set(name string, val Object) Object {
-	if _, ok := e.store[name]; ok {
-		e.store[name] = val
-		return val
+// Set stores val in slot of the environment depth levels out.
+func (e *Environment) Set(depth, slot int, val Object) {
+	env := e.ancestor(depth)
+	if slot >= len(env.slots) {
+		env.slots = append(env.slots, make([]Object, slot+1-len(env.slots))...)
 	}
 
-	if e.outer != nil {
-		if _, ok := e.outer.Get(name); ok {
-			return e.outer.Set(name, val)
-		}
+	env.slots[slot] = val
+}
+
+func (e *Environment) ancestor(depth int) *Environment {
+	env := e
+	for ; depth > 0; depth-- {
+		env = env.outer
 	}
 
-	e.store[name] = val
-	return val
+	return env
 }
 
 func NewEnclosedEnvironment(outer *Environment) *Environment {
-	env := NewEnvironment()
+	env := NewModuleEnvironment(outer.runtime)
 	env.outer = outer
 	return env
 }
@@ -0,0 +1,197 @@
+package object
+
+import (
+	"cmp"
+	"math"
+	"strings"
+)
+
+// Equals reports whether two objects hold the same value. Integers and floats
+// compare numerically, arrays, hashes and instances of the same struct compare
+// element by element and everything else (functions, builtins) compares by
+// identity.
+func Equals(a, b Object) bool {
+	return equals(a, b, nil)
+}
+
+// comparison is two containers being compared.
+type comparison struct{ a, b Object }
+
+// equals compares a and b like Equals. seen holds the containers being
+// compared: comparing two of them again inside themselves, which only happens
+// for values containing themselves, adds nothing to the result.
+func equals(a, b Object, seen map[comparison]bool) bool {
+	switch a.(type) {
+	case *Array, *Hash, *Instance:
+		if seen[comparison{a, b}] {
+			return true
+		}
+
+		if seen == nil {
+			seen = map[comparison]bool{}
+		}
+
+		seen[comparison{a, b}] = true
+		defer delete(seen, comparison{a, b})
+	}
+
+	switch a := a.(type) {
+	case *Integer:
+		switch b := b.(type) {
+		case *Integer:
+			return a.Value == b.Value
+		case *Float:
+			return float64(a.Value) == b.Value
+		}
+		return false
+
+	case *Float:
+		switch b := b.(type) {
+		case *Integer:
+			return a.Value == float64(b.Value)
+		case *Float:
+			return a.Value == b.Value
+		}
+		return false
+
+	case *String:
+		b, ok := b.(*String)
+		return ok && a.Value == b.Value
+
+	case *Boolean:
+		b, ok := b.(*Boolean)
+		return ok && a.Value == b.Value
+
+	case *Null:
+		_, ok := b.(*Null)
+		return ok
+
+	case *Array:
+		b, ok := b.(*Array)
+		if !ok || len(a.Elements) != len(b.Elements) {
+			return false
+		}
+
+		for i := range a.Elements {
+			if !equals(a.Elements[i], b.Elements[i], seen) {
+				return false
+			}
+		}
+
+		return true
+
+	case *Hash:
+		b, ok := b.(*Hash)
+		if !ok || a.Len() != b.Len() {
+			return false
+		}
+
+		for key, pair := range a.Pairs {
+			other, ok := b.Pairs[key]
+			if !ok || !equals(pair.Value, other.Value, seen) {
+				return false
+			}
+		}
+
+		return true
+
+	case *Instance:
+		b, ok := b.(*Instance)
+		if !ok || a.Struct != b.Struct {
+			return false
+		}
+
+		for i := range a.Fields {
+			if !equals(a.Fields[i], b.Fields[i], seen) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return a == b
+}
+
+// Unordered is what Compare returns for values of types that have an order but
+// not between these values, like NaN and any number. Every comparison between
+// them is false.
+const Unordered = math.MinInt
+
+// Compare orders two objects, returning a negative number, zero, a positive
+// number or Unordered. Numbers compare numerically, strings lexicographically
+// and arrays element by element. The second result is false when the types of
+// the objects have no ordering between them.
+func Compare(a, b Object) (int, bool) {
+	return compare(a, b, nil)
+}
+
+// compare orders a and b like Compare, seen holding the arrays being compared
+// like for equals.
+func compare(a, b Object, seen map[comparison]bool) (int, bool) {
+	switch a := a.(type) {
+	case *Integer:
+		switch b := b.(type) {
+		case *Integer:
+			return cmp.Compare(a.Value, b.Value), true
+		case *Float:
+			return compareFloats(float64(a.Value), b.Value), true
+		}
+
+	case *Float:
+		switch b := b.(type) {
+		case *Integer:
+			return compareFloats(a.Value, float64(b.Value)), true
+		case *Float:
+			return compareFloats(a.Value, b.Value), true
+		}
+
+	case *String:
+		if b, ok := b.(*String); ok {
+			return strings.Compare(a.Value, b.Value), true
+		}
+
+	case *Array:
+		if b, ok := b.(*Array); ok {
+			return compareArrays(a, b, seen)
+		}
+	}
+
+	return 0, false
+}
+
+// compareFloats is cmp.Compare, except that NaN is Unordered instead of less
+// than every other float.
+func compareFloats(a, b float64) int {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return Unordered
+	}
+
+	return cmp.Compare(a, b)
+}
+
+func compareArrays(a, b *Array, seen map[comparison]bool) (int, bool) {
+	if seen[comparison{a, b}] {
+		return 0, true
+	}
+
+	if seen == nil {
+		seen = map[comparison]bool{}
+	}
+
+	seen[comparison{a, b}] = true
+	defer delete(seen, comparison{a, b})
+
+	for i := 0; i < len(a.Elements) && i < len(b.Elements); i++ {
+		result, ok := compare(a.Elements[i], b.Elements[i], seen)
+		if !ok {
+			return 0, false
+		}
+
+		if result != 0 {
+			return result, true
+		}
+	}
+
+	return cmp.Compare(len(a.Elements), len(b.Elements)), true
+}
@@ -0,0 +1,118 @@
+package object
+
+import "math"
+
+// HashKey identifies a hash entry. Strings keep their whole value in Str, so
+// that two different strings never address the same entry.
+type HashKey struct {
+	Type  ObjectType
+	Value uint64
+	Str   string
+}
+
+// Hashable is implemented by every object that can be used as a hash key.
+type Hashable interface {
+	Object
+	HashKey() HashKey
+}
+
+func (s *String) HashKey() HashKey {
+	return HashKey{Type: s.Type(), Str: s.Value}
+}
+
+func (i *Integer) HashKey() HashKey {
+	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
+}
+
+// Floats holding a whole number hash like the equal integer, so that 1 and
+// 1.0 address the same hash entry.
+func (f *Float) HashKey() HashKey {
+	if f.Value == math.Trunc(f.Value) && math.Abs(f.Value) < math.MaxInt64 {
+		return HashKey{Type: IntegerObj, Value: uint64(int64(f.Value))}
+	}
+
+	return HashKey{Type: f.Type(), Value: math.Float64bits(f.Value)}
+}
+
+func (b *Boolean) HashKey() HashKey {
+	var value uint64
+	if b.Value {
+		value = 1
+	}
+
+	return HashKey{Type: b.Type(), Value: value}
+}
+
+func (n *Null) HashKey() HashKey {
+	return HashKey{Type: n.Type(), Value: 0}
+}
+
+type HashPair struct {
+	Key   Object
+	Value Object
+}
+
+type Hash struct {
+	Pairs map[HashKey]HashPair
+	keys  []HashKey // insertion order of Pairs
+}
+
+func NewHash() *Hash {
+	return &Hash{Pairs: make(map[HashKey]HashPair)}
+}
+
+func (h *Hash) Type() ObjectType { return HashObj }
+func (h *Hash) Inspect() string  { return inspect(h, nil) }
+
+func (h *Hash) Get(key Hashable) (Object, bool) {
+	pair, ok := h.Pairs[key.HashKey()]
+	if !ok {
+		return nil, false
+	}
+
+	return pair.Value, true
+}
+
+func (h *Hash) Set(key Hashable, value Object) {
+	hashKey := key.HashKey()
+
+	if pair, ok := h.Pairs[hashKey]; ok {
+		h.Pairs[hashKey] = HashPair{Key: pair.Key, Value: value}
+		return
+	}
+
+	h.keys = append(h.keys, hashKey)
+	h.Pairs[hashKey] = HashPair{Key: key, Value: value}
+}
+
+func (h *Hash) Delete(key Hashable) bool {
+	hashKey := key.HashKey()
+
+	if _, ok := h.Pairs[hashKey]; !ok {
+		return false
+	}
+
+	delete(h.Pairs, hashKey)
+
+	for i, k := range h.keys {
+		if k == hashKey {
+			h.keys = append(h.keys[:i], h.keys[i+1:]...)
+			break
+		}
+	}
+
+	return true
+}
+
+// Entries returns the pairs of the hash in insertion order.
+func (h *Hash) Entries() []HashPair {
+	entries := make([]HashPair, 0, len(h.keys))
+
+	for _, k := range h.keys {
+		entries = append(entries, h.Pairs[k])
+	}
+
+	return entries
+}
+
+func (h *Hash) Len() int { return len(h.keys) }
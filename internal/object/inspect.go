@@ -0,0 +1,81 @@
+package object
+
+import "strings"
+
+// inspect formats obj like its Inspect method. seen holds the arrays, hashes
+// and instances being formatted: one met again inside itself is shown as
+// [...] or {...} instead of being formatted forever.
+func inspect(obj Object, seen map[Object]bool) string {
+	switch obj.(type) {
+	case *Array, *Hash, *Instance, *ClassInstance:
+	default:
+		return obj.Inspect()
+	}
+
+	if seen[obj] {
+		if _, ok := obj.(*Array); ok {
+			return "[...]"
+		}
+
+		return "{...}"
+	}
+
+	if seen == nil {
+		seen = map[Object]bool{}
+	}
+
+	seen[obj] = true
+	defer delete(seen, obj)
+
+	var out strings.Builder
+
+	switch obj := obj.(type) {
+	case *Array:
+		out.WriteString("[")
+
+		for n, element := range obj.Elements {
+			if n > 0 {
+				out.WriteString(", ")
+			}
+
+			out.WriteString(inspect(element, seen))
+		}
+
+		out.WriteString("]")
+
+	case *Hash:
+		out.WriteString("{")
+		inspectPairs(&out, obj, seen)
+		out.WriteString("}")
+
+	case *Instance:
+		out.WriteString(obj.Struct.Name + "{")
+
+		for n, field := range obj.Struct.Fields {
+			if n > 0 {
+				out.WriteString(", ")
+			}
+
+			out.WriteString(field + ": " + inspect(obj.Fields[n], seen))
+		}
+
+		out.WriteString("}")
+
+	case *ClassInstance:
+		out.WriteString(obj.Class.Name + "{")
+		inspectPairs(&out, obj.Fields, seen)
+		out.WriteString("}")
+	}
+
+	return out.String()
+}
+
+func inspectPairs(out *strings.Builder, h *Hash, seen map[Object]bool) {
+	for n, pair := range h.Entries() {
+		if n > 0 {
+			out.WriteString(", ")
+		}
+
+		out.WriteString(pair.Key.Inspect() + ": " + inspect(pair.Value, seen))
+	}
+}
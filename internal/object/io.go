@@ -0,0 +1,29 @@
+package object
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// ReadLine reads a line from Stdin, without its line ending. The result is
+// false at the end of the input.
+func (r *Runtime) ReadLine() (string, bool, error) {
+	if r.buffered != r.Stdin {
+		r.stdin = bufio.NewReader(r.Stdin)
+		r.buffered = r.Stdin
+	}
+
+	line, err := r.stdin.ReadString('\n')
+	if err == io.EOF {
+		return line, line != "", nil
+	}
+
+	if err != nil {
+		return "", false, err
+	}
+
+	line = strings.TrimSuffix(line, "\n")
+
+	return strings.TrimSuffix(line, "\r"), true, nil
+}
@@ -0,0 +1,96 @@
+package object
+
+import "unicode/utf8"
+
+// Iterator walks the elements of an Iterable object.
+type Iterator interface {
+	// Next returns the key and value of the next element. The last result is
+	// false once the iterator is exhausted.
+	Next() (Object, Object, bool)
+}
+
+// Iterable is implemented by every object that can be used in a for-in loop.
+type Iterable interface {
+	Object
+	Iterator() Iterator
+}
+
+type arrayIterator struct {
+	array *Array
+	index int
+}
+
+func (a *Array) Iterator() Iterator { return &arrayIterator{array: a} }
+
+func (it *arrayIterator) Next() (Object, Object, bool) {
+	if it.index >= len(it.array.Elements) {
+		return nil, nil, false
+	}
+
+	key := &Integer{Value: int64(it.index)}
+	value := it.array.Elements[it.index]
+	it.index++
+
+	return key, value, true
+}
+
+// stringIterator yields the characters of a string, keyed by their index.
+type stringIterator struct {
+	value  string
+	offset int
+	index  int
+}
+
+func (s *String) Iterator() Iterator { return &stringIterator{value: s.Value} }
+
+func (it *stringIterator) Next() (Object, Object, bool) {
+	if it.offset >= len(it.value) {
+		return nil, nil, false
+	}
+
+	_, size := utf8.DecodeRuneInString(it.value[it.offset:])
+
+	key := &Integer{Value: int64(it.index)}
+	value := &String{Value: it.value[it.offset : it.offset+size]}
+	it.offset += size
+	it.index++
+
+	return key, value, true
+}
+
+type hashIterator struct {
+	entries []HashPair
+	index   int
+}
+
+func (h *Hash) Iterator() Iterator { return &hashIterator{entries: h.Entries()} }
+
+func (it *hashIterator) Next() (Object, Object, bool) {
+	if it.index >= len(it.entries) {
+		return nil, nil, false
+	}
+
+	pair := it.entries[it.index]
+	it.index++
+
+	return pair.Key, pair.Value, true
+}
+
+type rangeIterator struct {
+	rng   *Range
+	index int64
+}
+
+func (r *Range) Iterator() Iterator { return &rangeIterator{rng: r} }
+
+func (it *rangeIterator) Next() (Object, Object, bool) {
+	if it.index >= it.rng.Len() {
+		return nil, nil, false
+	}
+
+	key := &Integer{Value: it.index}
+	value := &Integer{Value: it.rng.Start + it.index*it.rng.Step}
+	it.index++
+
+	return key, value, true
+}
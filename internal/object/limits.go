@@ -0,0 +1,104 @@
+package object
+
+import (
+	"context"
+	"fmt"
+)
+
+// DefaultMaxDepth is the call depth of new runtimes, deep enough for real
+// programs while keeping runaway recursion from exhausting the Go stack.
+const DefaultMaxDepth = 10000
+
+// cancelCheckInterval is the number of steps between two checks of the
+// context of a runtime, which are too slow to make at every step.
+const cancelCheckInterval = 1024
+
+// elementSize is the approximate size of an element of an array.
+const elementSize = 16
+
+// Limits bound the resources a program may use. Zero fields are unlimited.
+type Limits struct {
+	MaxSteps int64 // nodes evaluated or instructions run
+	MaxDepth int   // nested function calls
+	MaxAlloc int64 // approximate bytes allocated for strings and arrays
+}
+
+// Reset starts counting the steps and allocations of a new run, which ctx can
+// cancel when it is not nil. It also forgets the calls and imports a run that
+// panicked never finished.
+func (r *Runtime) Reset(ctx context.Context) {
+	r.Context = ctx
+	r.cancelled = nil
+	r.steps = 0
+	r.depth = 0
+	r.allocated = 0
+	r.Importing = nil
+}
+
+// Step counts a step of the program, failing when the step limit is exceeded
+// or the context is done.
+func (r *Runtime) Step() *Error {
+	r.steps++
+
+	if r.Limits.MaxSteps > 0 && r.steps > r.Limits.MaxSteps {
+		return limitError("step limit of %d exceeded", r.Limits.MaxSteps)
+	}
+
+	// once done, the context stops every later step too, in case the step
+	// that noticed it was in a call the host made and ignored the error of
+	if r.cancelled == nil && r.Context != nil && r.steps%cancelCheckInterval == 0 {
+		r.cancelled = r.Context.Err()
+	}
+
+	if r.cancelled != nil {
+		return limitError("execution cancelled: %s", r.cancelled)
+	}
+
+	return nil
+}
+
+// Enter counts a function call, failing with a catchable error when it is
+// nested too deep. Every successful Enter must be followed by Leave.
+func (r *Runtime) Enter() *Error {
+	if r.Limits.MaxDepth > 0 && r.depth >= r.Limits.MaxDepth {
+		return &Error{Message: "stack overflow", Kind: RecursionError}
+	}
+
+	r.depth++
+
+	return nil
+}
+
+// Leave counts the return from a function call.
+func (r *Runtime) Leave() {
+	r.depth--
+}
+
+// Allocate counts the memory of obj if it is a new string or array, failing
+// when the allocation limit is exceeded.
+func (r *Runtime) Allocate(obj Object) *Error {
+	if r.Limits.MaxAlloc == 0 {
+		return nil
+	}
+
+	switch obj := obj.(type) {
+	case *String:
+		r.allocated += int64(len(obj.Value))
+	case *Array:
+		r.allocated += int64(len(obj.Elements)) * elementSize
+	default:
+		return nil
+	}
+
+	if r.allocated > r.Limits.MaxAlloc {
+		return limitError("allocation limit of %d bytes exceeded", r.Limits.MaxAlloc)
+	}
+
+	return nil
+}
+
+// limitError creates an error that try statements cannot catch, since the
+// program must stop.
+func limitError(format string, a ...interface{}) *Error {
+	return &Error{Message: fmt.Sprintf(format, a...), Kind: LimitError, Fatal: true}
+}
@@ -0,0 +1,53 @@
+package object
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+)
+
+// Module is an evaluated source file, exposing the bindings it exported.
+type Module struct {
+	Name    string
+	Path    string
+	Exports map[string]Object
+}
+
+func (m *Module) Type() ObjectType { return ModuleObj }
+func (m *Module) Inspect() string  { return "<module " + m.Name + ">" }
+
+// Runtime is the state shared by all the environments of a program, which
+// builtins receive as the context they run in.
+type Runtime struct {
+	SearchPath []string           // directories searched for imported modules
+	Modules    map[string]*Module // evaluated modules by absolute path
+	Importing  []string           // modules being evaluated, the innermost last
+
+	Stdin  io.Reader // read by input and readline
+	Stdout io.Writer // written by print and println
+	Stderr io.Writer // written by eprintln
+
+	Context context.Context // cancels the program when done, if not nil
+	Limits  Limits
+
+	stdin    *bufio.Reader // buffers the reader in buffered
+	buffered io.Reader
+
+	steps     int64
+	depth     int
+	allocated int64
+	cancelled error // the error of Context, once it is done
+}
+
+// NewRuntime creates a runtime using the standard input and outputs of the
+// process.
+func NewRuntime() *Runtime {
+	return &Runtime{
+		Modules: map[string]*Module{},
+		Stdin:   os.Stdin,
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+		Limits:  Limits{MaxDepth: DefaultMaxDepth},
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"strconv"
 	"strings"
 	"sunbird/internal/ast"
+	"sunbird/internal/diagnostic"
+	"sunbird/internal/token"
 )
 
 type ObjectType uint8
@@ -20,6 +22,18 @@ const (
 	ErrorObj
 	BuiltinObj
 	ArrayObj
+	HashObj
+	BreakObj
+	ContinueObj
+	RangeObj
+	ErrorValueObj
+	ModuleObj
+	CompiledFunctionObj
+	IteratorObj
+	StructObj
+	InstanceObj
+	ClassObj
+	BoundMethodObj
 )
 
 func (ot ObjectType) String() string {
@@ -44,6 +58,30 @@ func (ot ObjectType) String() string {
 		return "BUILTIN"
 	case ArrayObj:
 		return "ARRAY"
+	case HashObj:
+		return "HASH"
+	case BreakObj:
+		return "BREAK"
+	case ContinueObj:
+		return "CONTINUE"
+	case RangeObj:
+		return "RANGE"
+	case ErrorValueObj:
+		return "ERROR_VALUE"
+	case ModuleObj:
+		return "MODULE"
+	case CompiledFunctionObj:
+		return "COMPILED_FUNCTION"
+	case IteratorObj:
+		return "ITERATOR"
+	case StructObj:
+		return "STRUCT"
+	case InstanceObj:
+		return "INSTANCE"
+	case ClassObj:
+		return "CLASS"
+	case BoundMethodObj:
+		return "BOUND_METHOD"
 	default:
 		return "UNKNOWN"
 	}
@@ -94,17 +132,98 @@ type ReturnValue struct {
 func (rv *ReturnValue) Type() ObjectType { return ReturnValueObj }
 func (rv *ReturnValue) Inspect() string  { return rv.Value.Inspect() }
 
+// Break and Continue are control flow signals, they travel up through block
+// statements like ReturnValue until the enclosing loop handles them.
+type Break struct{}
+
+func (b *Break) Type() ObjectType { return BreakObj }
+func (b *Break) Inspect() string  { return "break" }
+
+type Continue struct{}
+
+func (c *Continue) Type() ObjectType { return ContinueObj }
+func (c *Continue) Inspect() string  { return "continue" }
+
+// Kinds of errors, seen by scripts as the type of a caught error.
+const (
+	GenericError      = "Error"
+	TypeError         = "TypeError"
+	NameError         = "NameError"
+	IndexError        = "IndexError"
+	ZeroDivisionError = "ZeroDivisionError"
+	ImportError       = "ImportError"
+	RecursionError    = "RecursionError"
+	LimitError        = "LimitError"
+	MatchError        = "MatchError"
+)
+
+// Error is a runtime error unwinding the evaluation, until a try statement
+// catches it or it ends the program.
 type Error struct {
 	Message string
+	Kind    string
+
+	Pos   token.Position // where the error happened
+	End   token.Position
+	Stack []Frame // the calls the error unwound, innermost first
+
+	Fatal bool // not catchable, like exceeding the limits of the runtime
+}
+
+// MaxStackFrames is the number of calls kept in the stack trace of an error,
+// which deep recursion would otherwise make huge.
+const MaxStackFrames = 100
+
+// AddFrame adds a call the error unwound to its stack trace.
+func (e *Error) AddFrame(frame Frame) {
+	if len(e.Stack) < MaxStackFrames {
+		e.Stack = append(e.Stack, frame)
+	}
+}
+
+// Frame is a function call on the stack.
+type Frame struct {
+	Function string
+	Pos      token.Position // the call site
 }
 
 func (e *Error) Type() ObjectType { return ErrorObj }
 func (e *Error) Inspect() string  { return "ERROR: " + e.Message }
 
+// Diagnostic returns the error as a diagnostic, with the stack trace in its
+// notes.
+func (e *Error) Diagnostic() diagnostic.Diagnostic {
+	span := diagnostic.Span{Start: e.Pos, End: e.End}
+	d := diagnostic.New(diagnostic.RuntimeError, span, "%s", e.Message)
+
+	if e.Kind != "" && e.Kind != GenericError {
+		d.Message = e.Kind + ": " + e.Message
+	}
+
+	for _, frame := range e.Stack {
+		d = d.WithNote("in %s, called at %s", frame.Function, diagnostic.Location(frame.Pos))
+	}
+
+	return d
+}
+
+// ErrorValue is a caught error, which scripts can inspect and throw again.
+type ErrorValue struct {
+	Error *Error
+}
+
+func (ev *ErrorValue) Type() ObjectType { return ErrorValueObj }
+func (ev *ErrorValue) Inspect() string  { return ev.Error.Kind + ": " + ev.Error.Message }
+
 type Function struct {
-	Parameters []*ast.Identifier
+	Parameters []ast.Pattern
 	Body       *ast.BlockStatement
 	Env        *Environment
+
+	// closures created by the VM run bytecode instead of Body
+	Compiled *CompiledFunction
+	Free     []*Cell  // the captured variables
+	Globals  []Object // the globals of the module defining the closure
 }
 
 func (f *Function) Type() ObjectType { return FunctionObj }
@@ -127,7 +246,9 @@ func (f *Function) Inspect() string {
 	return out.String()
 }
 
-type BuiltinFunction func(args ...Object) Object
+// BuiltinFunction is the Go implementation of a builtin, called with the
+// runtime of the calling program.
+type BuiltinFunction func(rt *Runtime, args ...Object) Object
 
 type Builtin struct {
 	Fn BuiltinFunction
@@ -141,18 +262,4 @@ type Array struct {
 }
 
 func (ao *Array) Type() ObjectType { return ArrayObj }
-func (ao *Array) Inspect() string {
-	var out bytes.Buffer
-
-	elements := []string{}
-
-	for _, e := range ao.Elements {
-		elements = append(elements, e.Inspect())
-	}
-
-	out.WriteString("[")
-	out.WriteString(strings.Join(elements, ", "))
-	out.WriteString("]")
-
-	return out.String()
-}
+func (ao *Array) Inspect() string  { return inspect(ao, nil) }
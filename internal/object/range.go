@@ -0,0 +1,43 @@
+package object
+
+import (
+	"math"
+	"strconv"
+)
+
+// Range is a lazy sequence of integers from Start up to, but not including,
+// End. It never materializes its elements.
+type Range struct {
+	Start int64
+	End   int64
+	Step  int64
+}
+
+func (r *Range) Type() ObjectType { return RangeObj }
+func (r *Range) Inspect() string {
+	return "range(" + strconv.FormatInt(r.Start, 10) + ", " +
+		strconv.FormatInt(r.End, 10) + ", " + strconv.FormatInt(r.Step, 10) + ")"
+}
+
+// Len returns the number of integers the range produces. The distance
+// between the bounds may not fit an int64, so it is computed unsigned, and
+// lengths beyond the largest int64 are capped to it.
+func (r *Range) Len() int64 {
+	var distance, step uint64
+
+	switch {
+	case r.Step > 0 && r.Start < r.End:
+		distance, step = uint64(r.End)-uint64(r.Start), uint64(r.Step)
+	case r.Step < 0 && r.Start > r.End:
+		distance, step = uint64(r.Start)-uint64(r.End), -uint64(r.Step)
+	default:
+		return 0
+	}
+
+	n := (distance-1)/step + 1
+	if n > math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return int64(n)
+}
@@ -0,0 +1,37 @@
+package object
+
+// StructType is a struct declared by a program, which instances are created
+// from.
+type StructType struct {
+	Name   string
+	Fields []string
+
+	index map[string]int // the positions of Fields
+}
+
+func NewStructType(name string, fields []string) *StructType {
+	st := &StructType{Name: name, Fields: fields, index: make(map[string]int, len(fields))}
+	for i, field := range fields {
+		st.index[field] = i
+	}
+
+	return st
+}
+
+func (st *StructType) Type() ObjectType { return StructObj }
+func (st *StructType) Inspect() string  { return "<struct " + st.Name + ">" }
+
+// FieldIndex returns the position of a field in the fields of instances.
+func (st *StructType) FieldIndex(name string) (int, bool) {
+	i, ok := st.index[name]
+	return i, ok
+}
+
+// Instance is a value of a struct type, holding a value for each field.
+type Instance struct {
+	Struct *StructType
+	Fields []Object // in the order of the fields of the struct
+}
+
+func (i *Instance) Type() ObjectType { return InstanceObj }
+func (i *Instance) Inspect() string  { return inspect(i, nil) }
@@ -9,6 +9,7 @@ func (p *Parser) parseArrayLiteral() ast.Expression {
 	array := &ast.ArrayLiteral{Token: p.curToken}
 
 	array.Elements = p.parseExpressionList(token.RBracket)
+	array.Rbracket = p.curToken
 
 	return array
 }
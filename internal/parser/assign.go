@@ -2,20 +2,46 @@ package parser
 
 import (
 	"sunbird/internal/ast"
+	"sunbird/internal/diagnostic"
 	"sunbird/internal/token"
 )
 
-func (p *Parser) parseAssignStatement() *ast.AssignStatement {
-	stmt := &ast.AssignStatement{Token: p.curToken}
+func isAssignOperator(t token.TokenType) bool {
+	switch t {
+	case token.Assign, token.PlusAssign, token.MinusAssign, token.AsteriskAssign,
+		token.SlashAssign, token.PercentAssign:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseSimpleStatement parses an expression statement, or an assignment if
+// the expression is followed by an assignment operator.
+func (p *Parser) parseSimpleStatement() ast.Statement {
+	startToken := p.curToken
+
+	exp := p.parseExpression(LOWEST)
 
-	if !p.curTokenIs(token.Ident) {
-		return nil
+	if isAssignOperator(p.peekToken.Type) {
+		p.nextToken()
+		return p.parseAssignStatement(startToken, exp)
 	}
 
-	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	stmt := &ast.ExpressionStatement{Token: startToken, Expression: exp}
 
-	if !p.expectPeek(token.Assign) {
-		return nil
+	if p.peekTokenIs(token.Semicolon) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseAssignStatement(startToken token.Token, target ast.Expression) ast.Statement {
+	stmt := &ast.AssignStatement{
+		Token:    startToken,
+		Target:   target,
+		Operator: p.curToken.Literal,
 	}
 
 	p.nextToken()
@@ -26,5 +52,14 @@ func (p *Parser) parseAssignStatement() *ast.AssignStatement {
 		p.nextToken()
 	}
 
-	return stmt
+	switch target.(type) {
+	case *ast.Identifier, *ast.IndexExpression, *ast.MemberExpression:
+		return stmt
+	default:
+		span := diagnostic.Span{Start: target.Pos(), End: target.End()}
+		d := diagnostic.New(diagnostic.InvalidAssignment, span, "cannot assign to %s", target)
+		p.report(d.WithNote("only variables, index expressions and fields can be assigned to"))
+
+		return &ast.BadStatement{Token: startToken, From: stmt.Pos(), To: stmt.End()}
+	}
 }
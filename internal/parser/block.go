@@ -9,14 +9,33 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{Token: p.curToken}
 	block.Statements = []ast.Statement{}
 
+	// after a syntax error the whole block gets skipped when recovering
+	if p.panicking {
+		return block
+	}
+
+	p.blockDepth++
+	defer func() { p.blockDepth-- }()
+	defer p.controlClause(false)()
+
 	p.nextToken()
 
 	for !p.curTokenIs(token.RBrace) && !p.curTokenIs(token.EOF) {
 		stmt := p.parseStatement()
 		block.Statements = append(block.Statements, stmt)
 
+		// a statement cut short by the closing brace leaves it as the current
+		// token, so it still ends the block
+		if _, bad := stmt.(*ast.BadStatement); bad && p.curTokenIs(token.RBrace) {
+			continue
+		}
+
 		p.nextToken()
 	}
 
+	if p.curTokenIs(token.RBrace) {
+		block.Rbrace = p.curToken
+	}
+
 	return block
 }
@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/diagnostic"
+	"sunbird/internal/token"
+)
+
+func (p *Parser) parseBreakStatement() *ast.BreakStatement {
+	stmt := &ast.BreakStatement{Token: p.curToken}
+
+	p.checkInsideLoop()
+
+	if p.peekTokenIs(token.Semicolon) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseContinueStatement() *ast.ContinueStatement {
+	stmt := &ast.ContinueStatement{Token: p.curToken}
+
+	p.checkInsideLoop()
+
+	if p.peekTokenIs(token.Semicolon) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) checkInsideLoop() {
+	if p.loopDepth > 0 {
+		return
+	}
+
+	d := diagnostic.New(diagnostic.LoopControlOutside, tokenSpan(p.curToken),
+		"%s outside of loop", p.curToken.Literal)
+	p.report(d.WithNote("break and continue can only be used inside for and while loops"))
+}
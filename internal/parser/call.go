@@ -12,5 +12,7 @@ func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 		Arguments: p.parseExpressionList(token.RParen),
 	}
 
+	exp.Rparen = p.curToken
+
 	return exp
 }
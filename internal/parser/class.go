@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/token"
+)
+
+// parseClassStatement parses class Dog extends Animal { ... }, which declares
+// the variable Dog holding the class.
+func (p *Parser) parseClassStatement() *ast.VarStatement {
+	def := &ast.ClassDefinition{Token: p.curToken, Methods: []*ast.FunctionLiteral{}}
+
+	if !p.expectPeek(token.Ident) {
+		return nil
+	}
+
+	def.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIsWord("extends") {
+		p.nextToken()
+		p.nextToken()
+
+		restore := p.controlClause(true)
+		def.Superclass = p.parseExpression(LOWEST)
+		restore()
+	}
+
+	if !p.expectPeek(token.LBrace) {
+		return nil
+	}
+
+	names := []*ast.Identifier{}
+
+	for !p.peekTokenIs(token.RBrace) {
+		if !p.expectPeek(token.Ident) {
+			return nil
+		}
+
+		name := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		p.checkDuplicateMember(names, name, "method")
+		names = append(names, name)
+
+		method := &ast.FunctionLiteral{Token: p.curToken}
+		if !p.parseFunction(method) {
+			return nil
+		}
+
+		def.Methods = append(def.Methods, method)
+	}
+
+	p.nextToken()
+	def.Rbrace = p.curToken
+
+	if p.peekTokenIs(token.Semicolon) {
+		p.nextToken()
+	}
+
+	return &ast.VarStatement{Token: def.Token, Name: def.Name, Value: def}
+}
+
+func (p *Parser) parseSuperExpression() ast.Expression {
+	exp := &ast.SuperExpression{
+		Token: p.curToken,
+		Class: &ast.Identifier{Token: p.curToken, Value: "super"},
+		Self:  &ast.Identifier{Token: p.curToken, Value: "self"},
+	}
+
+	if !p.expectPeek(token.Dot) || !p.expectPeek(token.Ident) {
+		return nil
+	}
+
+	exp.Method = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	return exp
+}
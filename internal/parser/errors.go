@@ -1,20 +1,56 @@
 package parser
 
 import (
-	"fmt"
+	"sunbird/internal/diagnostic"
 	"sunbird/internal/token"
 )
 
+// Errors returns the messages of the errors found while parsing.
 func (p *Parser) Errors() []string {
+	return diagnostic.Messages(p.errors)
+}
+
+func (p *Parser) Diagnostics() []diagnostic.Diagnostic {
 	return p.errors
 }
 
+// report adds an error, unless the parser is recovering from a syntax error
+// in which case it's most likely a consequence of that one.
+func (p *Parser) report(d diagnostic.Diagnostic) {
+	if p.panicking {
+		return
+	}
+
+	p.errors = append(p.errors, d)
+}
+
+// fail reports a syntax error and makes the parser give up on the current
+// statement. Errors found at illegal tokens were already reported by the
+// lexer.
+func (p *Parser) fail(d diagnostic.Diagnostic, tok token.Token) {
+	if tok.Type != token.Illegal {
+		p.report(d)
+	}
+
+	p.panicking = true
+}
+
+func (p *Parser) error(tok token.Token, code diagnostic.Code, format string, a ...interface{}) {
+	p.fail(diagnostic.New(code, tokenSpan(tok), format, a...), tok)
+}
+
+func tokenSpan(tok token.Token) diagnostic.Span {
+	return diagnostic.Span{Start: tok.Pos, End: tok.End}
+}
+
 func (p *Parser) peekError(t token.TokenType) {
-	msg := fmt.Sprintf("expected next token to be %s, got %s instead", t, p.peekToken.Type)
-	p.errors = append(p.errors, msg)
+	p.error(p.peekToken, diagnostic.UnexpectedToken,
+		"expected next token to be %s, got %s instead", t, p.peekToken.Type)
 }
 
 func (p *Parser) noPrefixParseFnError(t token.TokenType) {
-	msg := fmt.Sprintf("no prefix parse function for %s found", t)
-	p.errors = append(p.errors, msg)
+	d := diagnostic.New(diagnostic.MissingExpression, tokenSpan(p.curToken),
+		"no prefix parse function for %s found", t)
+
+	p.fail(d.WithNote("an expression was expected here"), p.curToken)
 }
@@ -1,9 +1,9 @@
 package parser
 
 import (
-	"fmt"
 	"strconv"
 	"sunbird/internal/ast"
+	"sunbird/internal/diagnostic"
 )
 
 func (p *Parser) parseFloatLiteral() ast.Expression {
@@ -12,9 +12,9 @@ func (p *Parser) parseFloatLiteral() ast.Expression {
 	value, err := strconv.ParseFloat(p.curToken.Literal, 64)
 
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as float", p.curToken.Literal)
-		p.errors = append(p.errors, msg)
-		return nil
+		p.report(diagnostic.New(diagnostic.InvalidNumber, tokenSpan(p.curToken),
+			"could not parse %q as float", p.curToken.Literal))
+		return p.badExpression()
 	}
 
 	lit.Value = value
@@ -5,15 +5,21 @@ import (
 	"sunbird/internal/token"
 )
 
-func (p *Parser) parseForStatement() *ast.ForStatement {
+func (p *Parser) parseForStatement() ast.Statement {
+	defer p.controlClause(true)()
+
 	stmt := &ast.ForStatement{Token: p.curToken}
 
 	p.nextToken()
 
+	if p.isForInStatement() {
+		return p.parseForInStatement(stmt.Token)
+	}
+
 	if p.curTokenIs(token.Var) {
 		stmt.Init = p.parseVarStatement()
 	} else if p.curTokenIs(token.Ident) {
-		stmt.Init = p.parseAssignStatement()
+		stmt.Init = p.parseSimpleStatement()
 	}
 
 	if !p.curTokenIs(token.Semicolon) {
@@ -31,14 +37,18 @@ func (p *Parser) parseForStatement() *ast.ForStatement {
 	p.nextToken()
 
 	if p.curTokenIs(token.Ident) {
-		stmt.Update = p.parseAssignStatement()
+		stmt.Update = p.parseSimpleStatement()
 	}
 
 	if !p.expectPeek(token.LBrace) {
 		return nil
 	}
 
-	stmt.Body = p.parseBlockStatement()
+	stmt.Body = p.parseLoopBody()
+
+	if p.peekTokenIs(token.Semicolon) {
+		p.nextToken()
+	}
 
 	return stmt
 }
@@ -0,0 +1,47 @@
+package parser
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/token"
+)
+
+func (p *Parser) isForInStatement() bool {
+	return p.curTokenIs(token.Ident) && (p.peekTokenIs(token.In) || p.peekTokenIs(token.Comma))
+}
+
+func (p *Parser) parseForInStatement(forToken token.Token) ast.Statement {
+	stmt := &ast.ForInStatement{Token: forToken}
+
+	stmt.Item = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.Comma) {
+		p.nextToken()
+
+		if !p.expectPeek(token.Ident) {
+			return nil
+		}
+
+		stmt.Index = stmt.Item
+		stmt.Item = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if !p.expectPeek(token.In) {
+		return nil
+	}
+
+	p.nextToken()
+
+	stmt.Iterable = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.LBrace) {
+		return nil
+	}
+
+	stmt.Body = p.parseLoopBody()
+
+	if p.peekTokenIs(token.Semicolon) {
+		p.nextToken()
+	}
+
+	return stmt
+}
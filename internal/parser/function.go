@@ -8,45 +8,63 @@ import (
 func (p *Parser) parseFunctionLiteral() ast.Expression {
 	lit := &ast.FunctionLiteral{Token: p.curToken}
 
-	if !p.expectPeek(token.LParen) {
+	if !p.parseFunction(lit) {
 		return nil
 	}
 
+	return lit
+}
+
+// parseFunction parses the parameters and the body of a function, which
+// follow the current token.
+func (p *Parser) parseFunction(lit *ast.FunctionLiteral) bool {
+	if !p.expectPeek(token.LParen) {
+		return false
+	}
+
 	lit.Parameters = p.parseFunctionParameters()
 
 	if !p.expectPeek(token.LBrace) {
-		return nil
+		return false
 	}
 
+	// break and continue can't cross a function boundary
+	loopDepth := p.loopDepth
+	p.loopDepth = 0
 	lit.Body = p.parseBlockStatement()
+	p.loopDepth = loopDepth
 
-	return lit
+	return true
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
-	identifiers := []*ast.Identifier{}
+func (p *Parser) parseFunctionParameters() []ast.Pattern {
+	params := []ast.Pattern{}
 
 	if p.peekTokenIs(token.RParen) {
 		p.nextToken()
-		return identifiers
+		return params
 	}
 
-	p.nextToken()
+	for {
+		p.nextToken()
 
-	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-	identifiers = append(identifiers, ident)
+		param := p.parseBindingPattern()
+		if param == nil {
+			return nil
+		}
 
-	for p.peekTokenIs(token.Comma) {
-		p.nextToken()
-		p.nextToken()
+		params = append(params, param)
+
+		if !p.peekTokenIs(token.Comma) {
+			break
+		}
 
-		ident = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-		identifiers = append(identifiers, ident)
+		p.nextToken()
 	}
 
 	if !p.expectPeek(token.RParen) {
 		return nil
 	}
 
-	return identifiers
+	return params
 }
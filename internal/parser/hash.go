@@ -0,0 +1,38 @@
+package parser
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/token"
+)
+
+func (p *Parser) parseHashLiteral() ast.Expression {
+	defer p.controlClause(false)()
+
+	hash := &ast.HashLiteral{Token: p.curToken, Pairs: []ast.HashPair{}}
+
+	for !p.peekTokenIs(token.RBrace) {
+		p.nextToken()
+		key := p.parseExpression(LOWEST)
+
+		if !p.expectPeek(token.Colon) {
+			return nil
+		}
+
+		p.nextToken()
+		value := p.parseExpression(LOWEST)
+
+		hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
+
+		if !p.peekTokenIs(token.RBrace) && !p.expectPeek(token.Comma) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBrace) {
+		return nil
+	}
+
+	hash.Rbrace = p.curToken
+
+	return hash
+}
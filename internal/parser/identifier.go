@@ -1,7 +1,17 @@
 package parser
 
-import "sunbird/internal/ast"
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/token"
+)
 
 func (p *Parser) parseIdentifier() ast.Expression {
-	return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.LBrace) && !p.inControlClause {
+		p.nextToken()
+		return p.parseStructLiteral(ident)
+	}
+
+	return ident
 }
@@ -6,6 +6,8 @@ import (
 )
 
 func (p *Parser) parseIfExpression() ast.Expression {
+	defer p.controlClause(true)()
+
 	expression := &ast.IfExpression{Token: p.curToken}
 
 	p.nextToken()
@@ -24,9 +26,13 @@ func (p *Parser) parseIfExpression() ast.Expression {
 		if p.peekTokenIs(token.If) {
 			p.nextToken()
 
+			// the else if has no braces, so its block starts at the if
+			ifToken := p.curToken
 			expression.Alternative = &ast.BlockStatement{
+				Token: ifToken,
 				Statements: []ast.Statement{
 					&ast.ExpressionStatement{
+						Token:      ifToken,
 						Expression: p.parseIfExpression(),
 					},
 				},
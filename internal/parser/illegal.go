@@ -0,0 +1,11 @@
+package parser
+
+import "sunbird/internal/ast"
+
+// parseIllegal gives up on the statement containing an illegal token. The
+// lexer has already reported why the token is illegal, so no extra error is
+// added here.
+func (p *Parser) parseIllegal() ast.Expression {
+	p.panicking = true
+	return p.badExpression()
+}
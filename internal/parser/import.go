@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/diagnostic"
+	"sunbird/internal/token"
+)
+
+func (p *Parser) parseImportStatement() *ast.ImportStatement {
+	stmt := &ast.ImportStatement{Token: p.curToken}
+
+	if p.peekTokenIs(token.LBrace) {
+		p.nextToken()
+
+		stmt.Names = []*ast.Identifier{}
+
+		for {
+			if !p.expectPeek(token.Ident) {
+				return nil
+			}
+
+			stmt.Names = append(stmt.Names, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+
+			if !p.peekTokenIs(token.Comma) {
+				break
+			}
+
+			p.nextToken()
+		}
+
+		if !p.expectPeek(token.RBrace) || !p.expectPeekWord("from") {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.String) {
+		return nil
+	}
+
+	stmt.Path = &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+
+	if stmt.Names == nil && p.peekTokenIsWord("as") {
+		p.nextToken()
+
+		if !p.expectPeek(token.Ident) {
+			return nil
+		}
+
+		stmt.Alias = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if p.peekTokenIs(token.Semicolon) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseExportStatement() *ast.ExportStatement {
+	stmt := &ast.ExportStatement{Token: p.curToken}
+
+	if p.blockDepth > 0 {
+		d := diagnostic.New(diagnostic.MisplacedExport, tokenSpan(p.curToken),
+			"export is only allowed at the top level of a module")
+		p.report(d)
+	}
+
+	switch {
+	case p.peekTokenIs(token.Struct):
+		p.nextToken()
+		stmt.Statement = p.parseStructStatement()
+
+		return stmt
+
+	case p.peekTokenIs(token.Class):
+		p.nextToken()
+		stmt.Statement = p.parseClassStatement()
+
+		return stmt
+	}
+
+	if !p.expectPeek(token.Var) {
+		return nil
+	}
+
+	stmt.Statement = p.parseVarStatement()
+
+	return stmt
+}
+
+// peekTokenIsWord reports whether the next token is the identifier word, for
+// words like from and as which are only keywords in import statements.
+func (p *Parser) peekTokenIsWord(word string) bool {
+	return p.peekTokenIs(token.Ident) && p.peekToken.Literal == word
+}
+
+func (p *Parser) expectPeekWord(word string) bool {
+	if p.peekTokenIsWord(word) {
+		p.nextToken()
+		return true
+	}
+
+	p.error(p.peekToken, diagnostic.UnexpectedToken,
+		"expected next token to be %s, got %s instead", word, p.peekToken.Type)
+	return false
+}
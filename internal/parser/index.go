@@ -6,7 +6,9 @@ import (
 )
 
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	exp := &ast.IndexExpression{Left: left}
+	defer p.controlClause(false)()
+
+	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
 
 	p.nextToken()
 
@@ -16,5 +18,7 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 		return nil
 	}
 
+	exp.Rbracket = p.curToken
+
 	return exp
 }
@@ -6,21 +6,31 @@ import (
 )
 
 var precedences = map[token.TokenType]int{
-	token.Or:       LOGICAL,
-	token.And:      LOGICAL,
-	token.Eq:       EQUALS,
-	token.NotEq:    EQUALS,
-	token.LT:       LESSGREATER,
-	token.GT:       LESSGREATER,
-	token.LE:       LESSGREATER,
-	token.GE:       LESSGREATER,
-	token.Plus:     SUM,
-	token.Minus:    SUM,
-	token.Slash:    PRODUCT,
-	token.Asterisk: PRODUCT,
-	token.LParen:   CALL,
-	token.Pipe:     PIPE,
-	token.LBracket: INDEX,
+	token.Or:         LOGICAL,
+	token.And:        LOGICAL,
+	token.Eq:         EQUALS,
+	token.NotEq:      EQUALS,
+	token.LT:         LESSGREATER,
+	token.GT:         LESSGREATER,
+	token.LE:         LESSGREATER,
+	token.GE:         LESSGREATER,
+	token.InstanceOf: LESSGREATER,
+	token.Plus:       SUM,
+	token.Minus:      SUM,
+	token.Slash:      PRODUCT,
+	token.Asterisk:   PRODUCT,
+	token.Percent:    PRODUCT,
+	token.FloorDiv:   PRODUCT,
+	token.Power:      POWER,
+	token.BitOr:      BITOR,
+	token.BitXor:     BITXOR,
+	token.BitAnd:     BITAND,
+	token.ShiftLeft:  SHIFT,
+	token.ShiftRight: SHIFT,
+	token.LParen:     CALL,
+	token.Pipe:       PIPE,
+	token.LBracket:   INDEX,
+	token.Dot:        INDEX,
 }
 
 func (p *Parser) peekPrecedence() int {
@@ -47,6 +57,12 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	}
 
 	precedence := p.curPrecedence()
+
+	// ** is right associative, so its right side may contain another **
+	if p.curTokenIs(token.Power) {
+		precedence--
+	}
+
 	p.nextToken()
 	expression.Right = p.parseExpression(precedence)
 
@@ -1,9 +1,9 @@
 package parser
 
 import (
-	"fmt"
 	"strconv"
 	"sunbird/internal/ast"
+	"sunbird/internal/diagnostic"
 )
 
 func (p *Parser) parseIntegerLiteral() ast.Expression {
@@ -12,9 +12,9 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
 
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %q as integer", p.curToken.Literal)
-		p.errors = append(p.errors, msg)
-		return nil
+		p.report(diagnostic.New(diagnostic.InvalidNumber, tokenSpan(p.curToken),
+			"could not parse %q as integer", p.curToken.Literal))
+		return p.badExpression()
 	}
 
 	lit.Value = value
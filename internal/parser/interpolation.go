@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/diagnostic"
+	"sunbird/internal/token"
+)
+
+func (p *Parser) parseInterpolatedString() ast.Expression {
+	str := &ast.InterpolatedString{Token: p.curToken}
+	str.Parts = []ast.Expression{p.parseStringSegment()}
+
+	for {
+		p.nextToken()
+
+		if p.curTokenIs(token.InterpMid) || p.curTokenIs(token.InterpEnd) {
+			p.error(p.curToken, diagnostic.MissingExpression, "empty expression in string interpolation")
+			return nil
+		}
+
+		str.Parts = append(str.Parts, p.parseExpression(LOWEST))
+
+		p.nextToken()
+		str.Parts = append(str.Parts, p.parseStringSegment())
+
+		switch p.curToken.Type {
+		case token.InterpMid:
+			continue
+		case token.InterpEnd:
+			return str
+		default:
+			p.error(p.curToken, diagnostic.UnexpectedToken,
+				"expected } to close string interpolation, got %s instead", p.curToken.Type)
+			return nil
+		}
+	}
+}
+
+func (p *Parser) parseStringSegment() *ast.StringLiteral {
+	return &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+}
@@ -0,0 +1,149 @@
+package parser
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/diagnostic"
+	"sunbird/internal/token"
+)
+
+func (p *Parser) parseMatchExpression() ast.Expression {
+	exp := &ast.MatchExpression{Token: p.curToken, Arms: []*ast.MatchArm{}}
+
+	p.nextToken()
+
+	restore := p.controlClause(true)
+	exp.Subject = p.parseExpression(LOWEST)
+	restore()
+
+	if !p.expectPeek(token.LBrace) {
+		return nil
+	}
+
+	defer p.controlClause(false)()
+
+	for !p.peekTokenIs(token.RBrace) {
+		p.nextToken()
+
+		arm := p.parseMatchArm()
+		if arm == nil {
+			return nil
+		}
+
+		exp.Arms = append(exp.Arms, arm)
+
+		// arms are separated by commas, which are optional after a block
+		switch {
+		case p.peekTokenIs(token.Comma):
+			p.nextToken()
+		case p.peekTokenIs(token.RBrace), arm.Body.Rbrace.End.IsValid():
+		default:
+			p.peekError(token.Comma)
+			return nil
+		}
+	}
+
+	p.nextToken()
+	exp.Rbrace = p.curToken
+
+	return exp
+}
+
+// parseMatchArm parses pattern | pattern if guard => body, starting at the
+// first pattern.
+func (p *Parser) parseMatchArm() *ast.MatchArm {
+	arm := &ast.MatchArm{}
+
+	for {
+		pattern := p.parsePattern()
+		if pattern == nil {
+			return nil
+		}
+
+		arm.Patterns = append(arm.Patterns, pattern)
+
+		if !p.peekTokenIs(token.BitOr) {
+			break
+		}
+
+		p.nextToken()
+		p.nextToken()
+	}
+
+	// only one of the alternatives matches, so the others could not bind
+	// their variables
+	if len(arm.Patterns) > 1 {
+		for _, pattern := range arm.Patterns {
+			ast.Inspect(pattern, func(node ast.Node) bool {
+				if ident, ok := node.(*ast.Identifier); ok {
+					p.report(diagnostic.New(diagnostic.InvalidPattern, tokenSpan(ident.Token),
+						"alternative patterns cannot bind variables"))
+				}
+
+				return true
+			})
+		}
+	}
+
+	if p.peekTokenIs(token.If) {
+		p.nextToken()
+		p.nextToken()
+		arm.Guard = p.parseExpression(LOWEST)
+	}
+
+	if !p.expectPeek(token.Arrow) {
+		return nil
+	}
+
+	p.nextToken()
+
+	if p.curTokenIs(token.LBrace) {
+		arm.Body = p.parseBlockStatement()
+		return arm
+	}
+
+	tok := p.curToken
+	arm.Body = &ast.BlockStatement{
+		Token:      tok,
+		Statements: []ast.Statement{&ast.ExpressionStatement{Token: tok, Expression: p.parseExpression(LOWEST)}},
+	}
+
+	return arm
+}
+
+func (p *Parser) parsePattern() ast.Pattern {
+	switch p.curToken.Type {
+	case token.Int, token.Float, token.String, token.True, token.False, token.Null:
+		return p.parseLiteralPattern()
+
+	case token.Minus:
+		if !p.peekTokenIs(token.Int) && !p.peekTokenIs(token.Float) {
+			break
+		}
+
+		return p.parseLiteralPattern()
+
+	case token.Ident:
+		if p.curToken.Literal == "_" {
+			return &ast.WildcardPattern{Token: p.curToken}
+		}
+
+		return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	case token.LBracket:
+		return p.parseArrayPattern(p.parsePattern)
+	}
+
+	d := diagnostic.New(diagnostic.InvalidPattern, tokenSpan(p.curToken), "expected a pattern, got %s", p.curToken.Type)
+	p.fail(d.WithNote("patterns are literals, names, _ or arrays of patterns"), p.curToken)
+
+	return nil
+}
+
+func (p *Parser) parseLiteralPattern() ast.Pattern {
+	value := p.prefixParseFns[p.curToken.Type]()
+	if value == nil {
+		return nil
+	}
+
+	return &ast.LiteralPattern{Value: value}
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"sunbird/internal/ast"
+	"sunbird/internal/diagnostic"
 	"sunbird/internal/lexer"
 	"sunbird/internal/token"
 )
@@ -10,7 +11,13 @@ type Parser struct {
 	l         *lexer.Lexer
 	curToken  token.Token
 	peekToken token.Token
-	errors    []string
+	errors    []diagnostic.Diagnostic
+
+	loopDepth       int  // number of loops enclosing the current token
+	blockDepth      int  // number of blocks enclosing the current token
+	panicking       bool // whether the current statement has a syntax error
+	inControlClause bool // whether struct literals need brackets, see controlClause
+	lexErrors       int  // number of lexer errors already copied into errors
 
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
@@ -26,48 +33,69 @@ const (
 	LOWEST
 	LOGICAL     // && or ||
 	EQUALS      // ==
-	LESSGREATER // >, <, <= or >=
+	LESSGREATER // >, <, <=, >= or instanceof
 	PIPE        // |>
+	BITOR       // |
+	BITXOR      // ^
+	BITAND      // &
+	SHIFT       // << or >>
 	SUM         // +
-	PRODUCT     // *
-	PREFIX      // -X or !X
+	PRODUCT     // *, /, ~/ or %
+	PREFIX      // -X, !X or ~X
+	POWER       // **
 	CALL        // foo()
-	INDEX       // arr[x]
+	INDEX       // arr[x] or x.y
 )
 
 func New(l *lexer.Lexer) *Parser {
-	p := &Parser{l: l, errors: []string{}}
+	p := &Parser{l: l, errors: []diagnostic.Diagnostic{}}
 
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
 	p.registerPrefix(token.Ident, p.parseIdentifier)
 	p.registerPrefix(token.Int, p.parseIntegerLiteral)
 	p.registerPrefix(token.Float, p.parseFloatLiteral)
 	p.registerPrefix(token.String, p.parseStringLiteral)
+	p.registerPrefix(token.InterpStart, p.parseInterpolatedString)
 	p.registerPrefix(token.Bang, p.parsePrefixExpression)
 	p.registerPrefix(token.Minus, p.parsePrefixExpression)
+	p.registerPrefix(token.BitNot, p.parsePrefixExpression)
 	p.registerPrefix(token.True, p.parseBoolean)
 	p.registerPrefix(token.False, p.parseBoolean)
 	p.registerPrefix(token.LParen, p.parseGroupedExpression)
 	p.registerPrefix(token.If, p.parseIfExpression)
 	p.registerPrefix(token.Function, p.parseFunctionLiteral)
 	p.registerPrefix(token.LBracket, p.parseArrayLiteral)
+	p.registerPrefix(token.LBrace, p.parseHashLiteral)
 	p.registerPrefix(token.Null, p.parseNullLiteral)
+	p.registerPrefix(token.Super, p.parseSuperExpression)
+	p.registerPrefix(token.Match, p.parseMatchExpression)
+	p.registerPrefix(token.Illegal, p.parseIllegal)
 
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 	p.registerInfix(token.Plus, p.parseInfixExpression)
 	p.registerInfix(token.Minus, p.parseInfixExpression)
 	p.registerInfix(token.Slash, p.parseInfixExpression)
 	p.registerInfix(token.Asterisk, p.parseInfixExpression)
+	p.registerInfix(token.Percent, p.parseInfixExpression)
+	p.registerInfix(token.FloorDiv, p.parseInfixExpression)
+	p.registerInfix(token.Power, p.parseInfixExpression)
+	p.registerInfix(token.BitAnd, p.parseInfixExpression)
+	p.registerInfix(token.BitOr, p.parseInfixExpression)
+	p.registerInfix(token.BitXor, p.parseInfixExpression)
+	p.registerInfix(token.ShiftLeft, p.parseInfixExpression)
+	p.registerInfix(token.ShiftRight, p.parseInfixExpression)
 	p.registerInfix(token.Eq, p.parseInfixExpression)
 	p.registerInfix(token.NotEq, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
 	p.registerInfix(token.LE, p.parseInfixExpression)
 	p.registerInfix(token.GE, p.parseInfixExpression)
+	p.registerInfix(token.InstanceOf, p.parseInfixExpression)
 	p.registerInfix(token.Or, p.parseInfixExpression)
 	p.registerInfix(token.And, p.parseInfixExpression)
 	p.registerInfix(token.LParen, p.parseCallExpression)
 	p.registerInfix(token.LBracket, p.parseIndexExpression)
+	p.registerInfix(token.Dot, p.parseMemberExpression)
 	p.registerInfix(token.Pipe, p.parseInfixExpression)
 
 	// Read 2 tokens so curToken and peekToken are set
@@ -80,6 +108,11 @@ func New(l *lexer.Lexer) *Parser {
 func (p *Parser) nextToken() {
 	p.curToken = p.peekToken
 	p.peekToken = p.l.NextToken()
+
+	if lexErrors := p.l.Diagnostics(); len(lexErrors) > p.lexErrors {
+		p.errors = append(p.errors, lexErrors[p.lexErrors:]...)
+		p.lexErrors = len(lexErrors)
+	}
 }
 
 func (p *Parser) curTokenIs(t token.TokenType) bool {
@@ -16,7 +16,7 @@ var y = 10;
 var foobar = 32.1;
   `
 
-	l := lexer.New(input)
+	l := lexer.New("", input)
 	p := parser.New(l)
 
 	program := p.ParseProgram()
@@ -76,7 +76,7 @@ func testVarStatement(t *testing.T, s ast.Statement, name string) bool {
 func TestAssignStatement(t *testing.T) {
 	input := "x = 4;"
 
-	l := lexer.New(input)
+	l := lexer.New("", input)
 	p := parser.New(l)
 
 	program := p.ParseProgram()
@@ -97,12 +97,12 @@ func TestAssignStatement(t *testing.T) {
 		t.Errorf("returnStmt.TokenLiteral not 'x', got %q", assignStmt.TokenLiteral())
 	}
 
-	if assignStmt.Name.Value != "x" {
-		t.Errorf("varStmt.Name.Value not '%s'. got=%s", "x", assignStmt.Name.Value)
+	if !testIdentifier(t, assignStmt.Target, "x") {
+		return
 	}
 
-	if assignStmt.Name.TokenLiteral() != "x" {
-		t.Errorf("assignStmt.Name not '%s'. got=%s", "x", assignStmt.Name)
+	if assignStmt.Operator != "=" {
+		t.Errorf("assignStmt.Operator not '='. got=%s", assignStmt.Operator)
 	}
 
 	if assignStmt.Value.String() != "4" {
@@ -116,7 +116,7 @@ return 6;
 return 1.24;
 return 993322;
 `
-	l := lexer.New(input)
+	l := lexer.New("", input)
 	p := parser.New(l)
 
 	program := p.ParseProgram()
@@ -162,7 +162,7 @@ func checkParserErrors(t *testing.T, p *parser.Parser) {
 func TestIdentifierExpression(t *testing.T) {
 	input := "foobar;"
 
-	l := lexer.New(input)
+	l := lexer.New("", input)
 	p := parser.New(l)
 	program := p.ParseProgram()
 	checkParserErrors(t, p)
@@ -191,7 +191,7 @@ func TestIdentifierExpression(t *testing.T) {
 
 func TestIntegerLiteralExpression(t *testing.T) {
 	input := "5;"
-	l := lexer.New(input)
+	l := lexer.New("", input)
 	p := parser.New(l)
 	program := p.ParseProgram()
 
@@ -228,7 +228,7 @@ func TestIntegerLiteralExpression(t *testing.T) {
 
 func TestFloatLiteralExpression(t *testing.T) {
 	input := "2.4;"
-	l := lexer.New(input)
+	l := lexer.New("", input)
 	p := parser.New(l)
 	program := p.ParseProgram()
 
@@ -264,7 +264,7 @@ func TestFloatLiteralExpression(t *testing.T) {
 
 func TestStringLiteralExpression(t *testing.T) {
 	input := `"hello world";`
-	l := lexer.New(input)
+	l := lexer.New("", input)
 	p := parser.New(l)
 	program := p.ParseProgram()
 
@@ -300,7 +300,7 @@ func TestStringLiteralExpression(t *testing.T) {
 
 func TestBooleanLiteralExpression(t *testing.T) {
 	input := "true;"
-	l := lexer.New(input)
+	l := lexer.New("", input)
 	p := parser.New(l)
 	program := p.ParseProgram()
 
@@ -344,10 +344,11 @@ func TestParsingPrefixExpressions(t *testing.T) {
 		{"-14", "-", 14},
 		{"!true", "!", true},
 		{"!false", "!", false},
+		{"~5", "~", 5},
 	}
 
 	for _, tt := range prefixTests {
-		l := lexer.New(tt.input)
+		l := lexer.New("", tt.input)
 		p := parser.New(l)
 		program := p.ParseProgram()
 		checkParserErrors(t, p)
@@ -482,6 +483,14 @@ func TestParsingInfixExpressions(t *testing.T) {
 		{"3.14 != 4;", 3.14, "!=", 4},
 		{"5.21 >= 5.21;", 5.21, ">=", 5.21},
 		{"5 <= 5;", 5, "<=", 5},
+		{"5 % 5;", 5, "%", 5},
+		{"5 ** 5;", 5, "**", 5},
+		{"5 ~/ 5;", 5, "~/", 5},
+		{"5 & 5;", 5, "&", 5},
+		{"5 | 5;", 5, "|", 5},
+		{"5 ^ 5;", 5, "^", 5},
+		{"5 << 5;", 5, "<<", 5},
+		{"5 >> 5;", 5, ">>", 5},
 		{"true || false;", true, "||", false},
 		{"true && false;", true, "&&", false},
 		{"true == true", true, "==", true},
@@ -492,7 +501,7 @@ func TestParsingInfixExpressions(t *testing.T) {
 	}
 
 	for _, tt := range infixTests {
-		l := lexer.New(tt.input)
+		l := lexer.New("", tt.input)
 		p := parser.New(l)
 		program := p.ParseProgram()
 
@@ -557,6 +566,19 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 		{"2 / (5 + 5)", "(2 / (5 + 5))"},
 		{"-(5 + 5)", "(-(5 + 5))"},
 		{"!(true == true)", "(!(true == true))"},
+		{"a % b * c", "((a % b) * c)"},
+		{"a + b % c", "(a + (b % c))"},
+		{"a ~/ b + c", "((a ~/ b) + c)"},
+		{"a ** b ** c", "(a ** (b ** c))"},
+		{"a * b ** c", "(a * (b ** c))"},
+		{"-a ** b", "(-(a ** b))"},
+		{"a ** -b", "(a ** (-b))"},
+		{"~a & b", "((~a) & b)"},
+		{"a | b ^ c & d", "(a | (b ^ (c & d)))"},
+		{"a & b << c", "(a & (b << c))"},
+		{"a << b + c", "(a << (b + c))"},
+		{"a >> 1 == b", "((a >> 1) == b)"},
+		{"a | b < c", "((a | b) < c)"},
 		{
 			"a + add(b * c) + d",
 			"((a + add((b * c))) + d)",
@@ -572,7 +594,7 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		l := lexer.New(tt.input)
+		l := lexer.New("", tt.input)
 		p := parser.New(l)
 
 		program := p.ParseProgram()
@@ -666,7 +688,7 @@ func testInfixExpression(
 func TestIfExpression(t *testing.T) {
 	input := "if x < 0 { x }"
 
-	l := lexer.New(input)
+	l := lexer.New("", input)
 	p := parser.New(l)
 	program := p.ParseProgram()
 	checkParserErrors(t, p)
@@ -716,7 +738,7 @@ func TestIfExpression(t *testing.T) {
 func TestIfElseExpression(t *testing.T) {
 	input := "if x < 0 { x } else { y }"
 
-	l := lexer.New(input)
+	l := lexer.New("", input)
 	p := parser.New(l)
 	program := p.ParseProgram()
 	checkParserErrors(t, p)
@@ -778,7 +800,7 @@ func TestIfElseExpression(t *testing.T) {
 // func TestIfElseExpression(t *testing.T) {
 //   input := "if x < 0 { x } else if x > 0 { y } else { 0 }"
 
-//   l := lexer.New(input)
+//   l := lexer.New("", input)
 //   p := parser.New(l)
 //   program := p.ParseProgram()
 //   checkParserErrors(t, p)
@@ -858,7 +880,7 @@ func TestIfElseExpression(t *testing.T) {
 func TestFunctionLiteralParsing(t *testing.T) {
 	input := "func (x, y) { x + y; }"
 
-	l := lexer.New(input)
+	l := lexer.New("", input)
 	p := parser.New(l)
 	program := p.ParseProgram()
 	checkParserErrors(t, p)
@@ -888,8 +910,8 @@ func TestFunctionLiteralParsing(t *testing.T) {
 		t.Fatalf("function literal parameters wrong. want 2, got=%d\n", len(function.Parameters))
 	}
 
-	testLiteralExpression(t, function.Parameters[0], "x")
-	testLiteralExpression(t, function.Parameters[1], "y")
+	testLiteralExpression(t, function.Parameters[0].(*ast.Identifier), "x")
+	testLiteralExpression(t, function.Parameters[1].(*ast.Identifier), "y")
 
 	if len(function.Body.Statements) != 1 {
 		t.Fatalf("function.Body.Statements has not 1 statements. got=%d\n",
@@ -918,7 +940,7 @@ func TestFunctionParameterParsing(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		l := lexer.New(tt.input)
+		l := lexer.New("", tt.input)
 		p := parser.New(l)
 		program := p.ParseProgram()
 		checkParserErrors(t, p)
@@ -935,14 +957,14 @@ func TestFunctionParameterParsing(t *testing.T) {
 		}
 
 		for i, ident := range tt.expectedParams {
-			testLiteralExpression(t, function.Parameters[i], ident)
+			testLiteralExpression(t, function.Parameters[i].(*ast.Identifier), ident)
 		}
 	}
 }
 
 func TestCallExpressionParsing(t *testing.T) {
 	input := "add(1, 2 * 3, 4 + 5);"
-	l := lexer.New(input)
+	l := lexer.New("", input)
 	p := parser.New(l)
 	program := p.ParseProgram()
 	checkParserErrors(t, p)
@@ -982,7 +1004,7 @@ func TestCallExpressionParsing(t *testing.T) {
 func TestParsingArrayLiterals(t *testing.T) {
 	input := "[1, 2 * 2, 3 + 3]"
 
-	l := lexer.New(input)
+	l := lexer.New("", input)
 	p := parser.New(l)
 	program := p.ParseProgram()
 	checkParserErrors(t, p)
@@ -1012,7 +1034,7 @@ func TestParsingArrayLiterals(t *testing.T) {
 func TestParsingIndexExpressions(t *testing.T) {
 	input := "coolArray[1 + 1]"
 
-	l := lexer.New(input)
+	l := lexer.New("", input)
 	p := parser.New(l)
 	program := p.ParseProgram()
 	checkParserErrors(t, p)
@@ -1057,7 +1079,7 @@ func TestForStatementParsing(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		l := lexer.New(tt.input)
+		l := lexer.New("", tt.input)
 		p := parser.New(l)
 		program := p.ParseProgram()
 		checkParserErrors(t, p)
@@ -1098,7 +1120,7 @@ func TestForStatementParsing(t *testing.T) {
 func TestPipeOperatorParsing(t *testing.T) {
 	input := "5 |> double"
 
-	l := lexer.New(input)
+	l := lexer.New("", input)
 	p := parser.New(l)
 	program := p.ParseProgram()
 	checkParserErrors(t, p)
@@ -1126,3 +1148,754 @@ func TestPipeOperatorParsing(t *testing.T) {
 	testIntegerLiteral(t, exp.Left, 5)
 	testIdentifier(t, exp.Right, "double")
 }
+
+func TestParsingHashLiteralsStringKeys(t *testing.T) {
+	input := `{"one": 1, "two": 2, "three": 3}`
+
+	l := lexer.New("", input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+	}
+
+	expected := []struct {
+		key   string
+		value int64
+	}{
+		{"one", 1},
+		{"two", 2},
+		{"three", 3},
+	}
+
+	if len(hash.Pairs) != len(expected) {
+		t.Fatalf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
+	}
+
+	for i, pair := range hash.Pairs {
+		testStringLiteral(t, pair.Key, expected[i].key)
+		testIntegerLiteral(t, pair.Value, expected[i].value)
+	}
+}
+
+func TestParsingEmptyHashLiteral(t *testing.T) {
+	input := "{}"
+
+	l := lexer.New("", input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(hash.Pairs) != 0 {
+		t.Errorf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
+	}
+}
+
+func TestParsingHashLiteralsWithExpressions(t *testing.T) {
+	input := `{"one": 0 + 1, "two": 10 - 8, "three": 15 / 5}`
+
+	l := lexer.New("", input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+	}
+
+	if len(hash.Pairs) != 3 {
+		t.Fatalf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
+	}
+
+	tests := map[string]func(ast.Expression){
+		"one": func(e ast.Expression) {
+			testInfixExpression(t, e, 0, "+", 1)
+		},
+		"two": func(e ast.Expression) {
+			testInfixExpression(t, e, 10, "-", 8)
+		},
+		"three": func(e ast.Expression) {
+			testInfixExpression(t, e, 15, "/", 5)
+		},
+	}
+
+	for _, pair := range hash.Pairs {
+		literal, ok := pair.Key.(*ast.StringLiteral)
+		if !ok {
+			t.Errorf("key is not ast.StringLiteral. got=%T", pair.Key)
+			continue
+		}
+
+		testFunc, ok := tests[literal.String()]
+		if !ok {
+			t.Errorf("No test function for key %q found", literal.String())
+			continue
+		}
+
+		testFunc(pair.Value)
+	}
+}
+
+func TestWhileStatementParsing(t *testing.T) {
+	input := "while x < 10 { x = x + 1; if x == 5 { continue } if x > 7 { break } }"
+
+	l := lexer.New("", input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statement. got=%d",
+			len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.WhileStatement)
+	if !ok {
+		t.Fatalf("program.Statements[0] is not ast.WhileStatement. got=%T",
+			program.Statements[0])
+	}
+
+	if !testInfixExpression(t, stmt.Condition, "x", "<", 10) {
+		return
+	}
+
+	if len(stmt.Body.Statements) != 3 {
+		t.Fatalf("body does not contain 3 statements. got=%d", len(stmt.Body.Statements))
+	}
+
+	expectedBody := "x = (x + 1);if(x == 5) continue;if(x > 7) break;"
+	if stmt.Body.String() != expectedBody {
+		t.Errorf("body wrong. expected=%q, got=%q", expectedBody, stmt.Body.String())
+	}
+}
+
+func TestLoopControlOutsideLoop(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"break", "break outside of loop"},
+		{"continue;", "continue outside of loop"},
+		{"if true { break }", "break outside of loop"},
+		{"while true { func() { continue } }", "continue outside of loop"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New("", tt.input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) != 1 {
+			t.Errorf("%q: expected 1 parser error. got=%v", tt.input, errors)
+			continue
+		}
+
+		if errors[0] != tt.expectedError {
+			t.Errorf("wrong error. expected=%q, got=%q", tt.expectedError, errors[0])
+		}
+	}
+}
+
+func TestForInStatementParsing(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedIndex    string
+		expectedItem     string
+		expectedIterable string
+		expectedBody     string
+	}{
+		{"for item in items { println(item) }", "", "item", "items", "println(item)"},
+		{"for i, c in \"abc\" { c }", "i", "c", "abc", "c"},
+		{"for x in range(1, 10) { x; }", "", "x", "range(1, 10)", "x"},
+		{"for k, v in {\"a\": 1} { k }", "k", "v", "{a: 1}", "k"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New("", tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got=%d",
+				len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ForInStatement)
+		if !ok {
+			t.Fatalf("program.Statements[0] is not ast.ForInStatement. got=%T",
+				program.Statements[0])
+		}
+
+		if tt.expectedIndex == "" && stmt.Index != nil {
+			t.Errorf("index should be nil. got=%q", stmt.Index.String())
+		}
+
+		if tt.expectedIndex != "" && !testIdentifier(t, stmt.Index, tt.expectedIndex) {
+			continue
+		}
+
+		if !testIdentifier(t, stmt.Item, tt.expectedItem) {
+			continue
+		}
+
+		if stmt.Iterable.String() != tt.expectedIterable {
+			t.Errorf("iterable wrong. expected=%q, got=%q",
+				tt.expectedIterable, stmt.Iterable.String())
+		}
+
+		if stmt.Body.String() != tt.expectedBody {
+			t.Errorf("body wrong. expected=%q, got=%q", tt.expectedBody, stmt.Body.String())
+		}
+	}
+}
+
+func TestLexerErrorsAreReported(t *testing.T) {
+	input := `var x = "unterminated`
+
+	l := lexer.New("", input)
+	p := parser.New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) == 0 || errors[0] != "unterminated string" {
+		t.Fatalf("expected unterminated string error. got=%q", errors)
+	}
+}
+
+func TestInterpolatedStringParsing(t *testing.T) {
+	input := `"Hello ${name}, you have ${len(items) + 1} items"`
+
+	l := lexer.New("", input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	str, ok := stmt.Expression.(*ast.InterpolatedString)
+	if !ok {
+		t.Fatalf("exp is not ast.InterpolatedString. got=%T", stmt.Expression)
+	}
+
+	if len(str.Parts) != 5 {
+		t.Fatalf("str.Parts has wrong length. got=%d", len(str.Parts))
+	}
+
+	testStringLiteral(t, str.Parts[0], "Hello ")
+	testIdentifier(t, str.Parts[1], "name")
+	testStringLiteral(t, str.Parts[2], ", you have ")
+
+	if str.Parts[3].String() != "(len(items) + 1)" {
+		t.Errorf("str.Parts[3] wrong. got=%q", str.Parts[3].String())
+	}
+
+	testStringLiteral(t, str.Parts[4], " items")
+}
+
+func TestInterpolatedStringErrors(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{`"a ${} b"`, "empty expression in string interpolation"},
+		{`"a ${x y} b"`, "expected } to close string interpolation, got IDENT instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New("", tt.input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expectedError {
+			t.Errorf("%s: wrong errors. expected first=%q, got=%q",
+				tt.input, tt.expectedError, errors)
+		}
+	}
+}
+
+func TestCompoundAndIndexAssignmentParsing(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedTarget   string
+		expectedOperator string
+		expectedValue    string
+	}{
+		{"x += 1;", "x", "+=", "1"},
+		{"x -= y * 2", "x", "-=", "(y * 2)"},
+		{"x *= 3", "x", "*=", "3"},
+		{"x /= 4", "x", "/=", "4"},
+		{"x %= 5", "x", "%=", "5"},
+		{"arr[0] = 1", "(arr[0])", "=", "1"},
+		{"arr[i + 1] += 2", "(arr[(i + 1)])", "+=", "2"},
+		{`h["k"] = v`, "(h[k])", "=", "v"},
+		{`grid[1][2] = 0`, "((grid[1])[2])", "=", "0"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New("", tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("%s: program.Statements does not contain 1 statement. got=%d",
+				tt.input, len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.AssignStatement)
+		if !ok {
+			t.Fatalf("%s: stmt not *ast.AssignStatement. got=%T", tt.input, program.Statements[0])
+		}
+
+		if stmt.Target.String() != tt.expectedTarget {
+			t.Errorf("target wrong. expected=%q, got=%q", tt.expectedTarget, stmt.Target.String())
+		}
+
+		if stmt.Operator != tt.expectedOperator {
+			t.Errorf("operator wrong. expected=%q, got=%q", tt.expectedOperator, stmt.Operator)
+		}
+
+		if stmt.Value.String() != tt.expectedValue {
+			t.Errorf("value wrong. expected=%q, got=%q", tt.expectedValue, stmt.Value.String())
+		}
+	}
+}
+
+func TestInvalidAssignmentTarget(t *testing.T) {
+	l := lexer.New("", "f() = 1")
+	p := parser.New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 || errors[0] != "cannot assign to f()" {
+		t.Fatalf("wrong errors. got=%q", errors)
+	}
+}
+
+func TestNodePositions(t *testing.T) {
+	tests := []struct {
+		input           string
+		line, col       int
+		endLine, endCol int
+	}{
+		{"var x = 1 + 2;", 1, 1, 1, 14},
+		{"foo(1, 2)", 1, 1, 1, 10},
+		{"arr[0]", 1, 1, 1, 7},
+		{"-a * b", 1, 1, 1, 7},
+		{"{\"a\": 1}", 1, 1, 1, 9},
+		{"[1, 2]", 1, 1, 1, 7},
+		{"return x", 1, 1, 1, 9},
+		{"x += 1", 1, 1, 1, 7},
+		{"func(x) {\n  x\n}", 1, 1, 3, 2},
+		{"if (a) { b } else if (c) {\n d }", 1, 1, 2, 5},
+		{"while (x) {\n}", 1, 1, 2, 2},
+		{"  \"a ${b} c\"", 1, 3, 1, 13},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New("", tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0]
+		pos, end := stmt.Pos(), stmt.End()
+
+		if pos.Line != tt.line || pos.Col != tt.col {
+			t.Errorf("%q: pos wrong. expected=%d:%d, got=%d:%d",
+				tt.input, tt.line, tt.col, pos.Line, pos.Col)
+		}
+
+		if end.Line != tt.endLine || end.Col != tt.endCol {
+			t.Errorf("%q: end wrong. expected=%d:%d, got=%d:%d",
+				tt.input, tt.endLine, tt.endCol, end.Line, end.Col)
+		}
+	}
+}
+
+func TestErrorRecovery(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedErrors []string
+		expectedTypes  []string
+	}{
+		{
+			"var = 5;\nvar y = 10;\nvar z = ;",
+			[]string{
+				"expected next token to be IDENT, got = instead",
+				"no prefix parse function for ; found",
+			},
+			[]string{"*ast.BadStatement", "*ast.VarStatement", "*ast.BadStatement"},
+		},
+		{
+			"foo(1, 2\nvar y = 1",
+			[]string{"expected next token to be ), got VAR instead"},
+			[]string{"*ast.BadStatement", "*ast.VarStatement"},
+		},
+		{
+			"x = ) ) );\ny = 2",
+			[]string{"no prefix parse function for ) found"},
+			[]string{"*ast.BadStatement", "*ast.AssignStatement"},
+		},
+		{
+			"var f = func(x {\n  return x\n}\nf(1)",
+			[]string{"expected next token to be ), got { instead"},
+			[]string{"*ast.BadStatement", "*ast.ExpressionStatement"},
+		},
+		{
+			"if (x) { 1 + }\nvar y = 2;",
+			[]string{"no prefix parse function for } found"},
+			[]string{"*ast.ExpressionStatement", "*ast.VarStatement"},
+		},
+		{
+			"var s = \"a\\qb\" + ;\nvar t = 1",
+			[]string{`unknown escape sequence: \q`},
+			[]string{"*ast.BadStatement", "*ast.VarStatement"},
+		},
+		{
+			"f() = 1; 2",
+			[]string{"cannot assign to f()"},
+			[]string{"*ast.BadStatement", "*ast.ExpressionStatement"},
+		},
+		{
+			"var p = (1 + 2 struct P { x }",
+			[]string{"expected next token to be ), got STRUCT instead"},
+			[]string{"*ast.BadStatement", "*ast.VarStatement"},
+		},
+		{
+			"var p = (1 + 2 class C { f() { 1 } }",
+			[]string{"expected next token to be ), got CLASS instead"},
+			[]string{"*ast.BadStatement", "*ast.VarStatement"},
+		},
+		{
+			"var p = (1 + 2 match p { _ => 1 }",
+			[]string{"expected next token to be ), got MATCH instead"},
+			[]string{"*ast.BadStatement", "*ast.ExpressionStatement"},
+		},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New("", tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+
+		errors := p.Errors()
+		if fmt.Sprint(errors) != fmt.Sprint(tt.expectedErrors) {
+			t.Errorf("%q: wrong errors. expected=%q, got=%q", tt.input, tt.expectedErrors, errors)
+		}
+
+		types := []string{}
+		for _, stmt := range program.Statements {
+			types = append(types, fmt.Sprintf("%T", stmt))
+		}
+
+		if fmt.Sprint(types) != fmt.Sprint(tt.expectedTypes) {
+			t.Errorf("%q: wrong statements. expected=%v, got=%v", tt.input, tt.expectedTypes, types)
+		}
+	}
+}
+
+func TestTryStatementParsing(t *testing.T) {
+	tests := []struct {
+		input      string
+		catchParam string
+		hasCatch   bool
+		hasFinally bool
+	}{
+		{"try { x } catch (e) { y }", "e", true, false},
+		{"try { x } finally { z }", "", false, true},
+		{"try { x } catch (err) { y } finally { z }", "err", true, true},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New("", tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.TryStatement)
+		if !ok {
+			t.Fatalf("stmt is not *ast.TryStatement. got=%T", program.Statements[0])
+		}
+
+		if len(stmt.Block.Statements) != 1 {
+			t.Errorf("try block does not contain 1 statement. got=%d", len(stmt.Block.Statements))
+		}
+
+		if (stmt.Catch != nil) != tt.hasCatch || (stmt.Finally != nil) != tt.hasFinally {
+			t.Errorf("%q: wrong blocks. catch=%v, finally=%v", tt.input, stmt.Catch != nil, stmt.Finally != nil)
+		}
+
+		if tt.hasCatch && stmt.CatchParam.Value != tt.catchParam {
+			t.Errorf("wrong catch parameter. expected=%q, got=%q", tt.catchParam, stmt.CatchParam.Value)
+		}
+	}
+}
+
+func TestTryWithoutCatchOrFinally(t *testing.T) {
+	l := lexer.New("", "try { x }")
+	p := parser.New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 || errors[0] != "expected catch or finally after try block, got EOF instead" {
+		t.Fatalf("wrong errors. got=%q", errors)
+	}
+}
+
+func TestThrowAndMemberParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`throw "boom"`, `throw boom;`},
+		{`throw error(e.message)`, `throw error((e.message));`},
+		{`e.stack[0]`, `((e.stack)[0])`},
+		{`a.b.c`, `((a.b).c)`},
+		{`-e.x * 2`, `((-(e.x)) * 2)`},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New("", tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestImportAndExportParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`import "math"`, `import "math";`},
+		{`import "./lib/util" as u;`, `import "./lib/util" as u;`},
+		{`import { a, b } from "math"`, `import { a, b } from "math";`},
+		{`export var x = 1;`, `export var x = 1;`},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New("", tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestMisplacedExport(t *testing.T) {
+	l := lexer.New("", "if (true) { export var x = 1; }")
+	p := parser.New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 || errors[0] != "export is only allowed at the top level of a module" {
+		t.Fatalf("wrong errors. got=%q", errors)
+	}
+}
+
+func TestStructParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`struct Point { x, y }`, `struct Point { x, y }`},
+		{`struct Empty {}`, `struct Empty {  }`},
+		{`export struct Point { x }`, `export struct Point { x }`},
+		{`Point{x: 1, y: 2 + 3}`, `Point{x: 1, y: (2 + 3)}`},
+		{`Point{}`, `Point{}`},
+		{`Line{from: Point{x: 1}, to: p}.from.x`, `((Line{from: Point{x: 1}, to: p}.from).x)`},
+		{`p.x = 1`, `(p.x) = 1;`},
+		{`p.x += 1`, `(p.x) += 1;`},
+		{`if p { x }`, `ifp x`},
+		{`if (Point{x: 1}) == p { x }`, `if(Point{x: 1} == p) x`},
+		{`while running { x }`, `while running x`},
+		{`for item in items { x }`, `for item in items x`},
+		{`if f(Point{x: 1}) { x }`, `iff(Point{x: 1}) x`},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New("", tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestDuplicateFields(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"struct Point { x, x }", "duplicate field x"},
+		{"Point{x: 1, x: 2}", "duplicate field x"},
+		{"class A { f() {} f() {} }", "duplicate method f"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New("", tt.input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) != 1 || errors[0] != tt.expected {
+			t.Errorf("%s: wrong errors. got=%q", tt.input, errors)
+		}
+	}
+}
+
+func TestClassParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`class Empty {}`, `class Empty { }`},
+		{`class Point { init(x) { self.x = x } norm() { self.x } }`, `class Point { init(x) (self.x) = x; norm() (self.x) }`},
+		{`class B extends A { f() { super.f() } }`, `class B extends A { f() super.f() }`},
+		{`class B extends m.A {}`, `class B extends (m.A) { }`},
+		{`export class A {}`, `export class A { }`},
+		{`a instanceof A == b < c`, `((a instanceof A) == (b < c))`},
+		{`a + b instanceof A`, `((a + b) instanceof A)`},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New("", tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestMatchParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`match x { 1 => a, _ => b }`, `match x { 1 => a, _ => b }`},
+		{`match x { -1 | 0 => a, "a" | 1.5 => b, }`, `match x { (-1) | 0 => a, a | 1.5 => b }`},
+		{`match x { [a, [b, _]] => a + b }`, `match x { [a, [b, _]] => (a + b) }`},
+		{`match x { n if n > 10 => n, null | true => 0 }`, `match x { n if (n > 10) => n, null | true => 0 }`},
+		{"match x { [] => { var y = 1\n y } _ => 2 }", `match x { [] => var y = 1;y, _ => 2 }`},
+		{`var y = match f(x) { _ => 1 } + 1`, `var y = (match f(x) { _ => 1 } + 1);`},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New("", tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestInvalidPatterns(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"match x { a + 1 => 1 }", "expected next token to be =>, got + instead"},
+		{"match x { f() => 1 }", "expected next token to be =>, got ( instead"},
+		{"match x { {} => 1 }", "expected a pattern, got {"},
+		{"match x { 1 | a => 1 }", "alternative patterns cannot bind variables"},
+		{"match x { 1 => 1 2 => 2 }", "expected next token to be ,, got INT instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New("", tt.input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("%s: wrong errors. got=%q", tt.input, errors)
+		}
+	}
+}
+
+func TestDestructuringParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"var [a, b] = x", "var [a, b] = x;"},
+		{"var [a, _, ...rest] = x", "var [a, _, ...rest] = x;"},
+		{"var [...rest] = x", "var [...rest] = x;"},
+		{"var {name, age: [a, b]} = x", "var {name, age: [a, b]} = x;"},
+		{"var {} = x", "var {} = x;"},
+		{"var [{a}, [b]] = x", "var [{a}, [b]] = x;"},
+		{"func([a, b], {c}, d) { a }", "func([a, b], {c}, d) a"},
+		{"match x { [a, ...rest] => rest }", "match x { [a, ...rest] => rest }"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New("", tt.input)
+		p := parser.New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestInvalidDestructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"var [1] = x", "expected a name or a pattern, got INT"},
+		{"func(1) { }", "expected a name or a pattern, got INT"},
+		{"var [...rest, a] = x", "expected next token to be ], got , instead"},
+		{"var [...[a]] = x", "expected next token to be IDENT, got [ instead"},
+		{"var {1} = x", "expected next token to be IDENT, got INT instead"},
+		{"var {a: 1} = x", "expected a name or a pattern, got INT"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New("", tt.input)
+		p := parser.New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("%s: wrong errors. got=%q", tt.input, errors)
+		}
+	}
+}
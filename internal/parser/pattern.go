@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/diagnostic"
+	"sunbird/internal/token"
+)
+
+// parseBindingPattern parses what a declaration or a parameter binds: a name,
+// or an array or hash pattern destructuring a value.
+func (p *Parser) parseBindingPattern() ast.Pattern {
+	switch p.curToken.Type {
+	case token.Ident:
+		return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	case token.LBracket:
+		return p.parseArrayPattern(p.parseBindingElement)
+
+	case token.LBrace:
+		return p.parseHashPattern()
+	}
+
+	d := diagnostic.New(diagnostic.InvalidPattern, tokenSpan(p.curToken), "expected a name or a pattern, got %s", p.curToken.Type)
+	p.fail(d.WithNote("declarations bind names, arrays like [a, b, ...rest] or hashes like {name, age}"), p.curToken)
+
+	return nil
+}
+
+// parseBindingElement parses a part of a destructuring pattern, which may be
+// _ to skip it.
+func (p *Parser) parseBindingElement() ast.Pattern {
+	if p.curTokenIs(token.Ident) && p.curToken.Literal == "_" {
+		return &ast.WildcardPattern{Token: p.curToken}
+	}
+
+	return p.parseBindingPattern()
+}
+
+// parseArrayPattern parses [element, ..., ...rest], with parseElement parsing
+// the elements.
+func (p *Parser) parseArrayPattern(parseElement func() ast.Pattern) ast.Pattern {
+	pattern := &ast.ArrayPattern{Token: p.curToken, Elements: []ast.Pattern{}}
+
+	for !p.peekTokenIs(token.RBracket) {
+		p.nextToken()
+
+		// the rest of the array can only come last
+		if p.curTokenIs(token.Ellipsis) {
+			if !p.expectPeek(token.Ident) {
+				return nil
+			}
+
+			pattern.Rest = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+			break
+		}
+
+		element := parseElement()
+		if element == nil {
+			return nil
+		}
+
+		pattern.Elements = append(pattern.Elements, element)
+
+		if !p.peekTokenIs(token.RBracket) && !p.expectPeek(token.Comma) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBracket) {
+		return nil
+	}
+
+	pattern.Rbracket = p.curToken
+
+	return pattern
+}
+
+// parseHashPattern parses {key, key: pattern, ...}.
+func (p *Parser) parseHashPattern() ast.Pattern {
+	pattern := &ast.HashPattern{Token: p.curToken, Keys: []*ast.Identifier{}, Values: []ast.Pattern{}}
+
+	for !p.peekTokenIs(token.RBrace) {
+		if !p.expectPeek(token.Ident) {
+			return nil
+		}
+
+		key := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+		var value ast.Pattern = key
+		if p.peekTokenIs(token.Colon) {
+			p.nextToken()
+			p.nextToken()
+
+			if value = p.parseBindingElement(); value == nil {
+				return nil
+			}
+		}
+
+		pattern.Keys = append(pattern.Keys, key)
+		pattern.Values = append(pattern.Values, value)
+
+		if !p.peekTokenIs(token.RBrace) && !p.expectPeek(token.Comma) {
+			return nil
+		}
+	}
+
+	p.nextToken()
+	pattern.Rbrace = p.curToken
+
+	return pattern
+}
@@ -0,0 +1,58 @@
+package parser
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/token"
+)
+
+// recoverStatement replaces a statement containing a syntax error by a
+// BadStatement, skipping ahead to where the next statement is likely to start.
+func (p *Parser) recoverStatement(start token.Token) *ast.BadStatement {
+	p.synchronize()
+	p.panicking = false
+
+	return &ast.BadStatement{Token: start, From: start.Pos, To: p.curToken.End}
+}
+
+// synchronize advances to the end of the current statement: a semicolon, or
+// the token before a closing brace, a keyword starting a statement or a new
+// line. Braces opened on the way are skipped as a whole.
+func (p *Parser) synchronize() {
+	depth := 0
+
+	for !p.curTokenIs(token.EOF) {
+		switch p.curToken.Type {
+		case token.LBrace:
+			depth++
+		case token.RBrace:
+			if depth > 0 {
+				depth--
+			}
+		case token.Semicolon:
+			if depth == 0 {
+				return
+			}
+		}
+
+		if depth == 0 && p.atStatementBoundary() {
+			return
+		}
+
+		p.nextToken()
+	}
+}
+
+func (p *Parser) atStatementBoundary() bool {
+	switch p.peekToken.Type {
+	case token.RBrace, token.EOF, token.Var, token.Return, token.If, token.Match,
+		token.For, token.While, token.Break, token.Continue, token.Throw,
+		token.Try, token.Import, token.Export, token.Struct, token.Class:
+		return true
+	}
+
+	return p.peekToken.Pos.Line > p.curToken.End.Line
+}
+
+func (p *Parser) badExpression() *ast.BadExpression {
+	return &ast.BadExpression{Token: p.curToken, From: p.curToken.Pos, To: p.curToken.End}
+}
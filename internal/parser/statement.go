@@ -6,23 +6,57 @@ import (
 )
 
 func (p *Parser) parseStatement() ast.Statement {
+	start := p.curToken
+
+	var stmt ast.Statement
+
 	switch p.curToken.Type {
 	case token.Var:
-		return p.parseVarStatement()
+		stmt = p.parseVarStatement()
 
 	case token.Return:
-		return p.parseReturnStatement()
+		stmt = p.parseReturnStatement()
 
 	case token.Ident:
-		if p.peekTokenIs(token.Assign) {
-			return p.parseAssignStatement()
-		}
-		return p.parseExpressionStatement()
+		stmt = p.parseSimpleStatement()
 
 	case token.For:
-		return p.parseForStatement()
+		stmt = p.parseForStatement()
+
+	case token.While:
+		stmt = p.parseWhileStatement()
+
+	case token.Break:
+		stmt = p.parseBreakStatement()
+
+	case token.Continue:
+		stmt = p.parseContinueStatement()
+
+	case token.Throw:
+		stmt = p.parseThrowStatement()
+
+	case token.Try:
+		stmt = p.parseTryStatement()
+
+	case token.Import:
+		stmt = p.parseImportStatement()
+
+	case token.Export:
+		stmt = p.parseExportStatement()
+
+	case token.Struct:
+		stmt = p.parseStructStatement()
+
+	case token.Class:
+		stmt = p.parseClassStatement()
 
 	default:
-		return p.parseExpressionStatement()
+		stmt = p.parseExpressionStatement()
 	}
+
+	if p.panicking {
+		return p.recoverStatement(start)
+	}
+
+	return stmt
 }
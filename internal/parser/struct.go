@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/diagnostic"
+	"sunbird/internal/token"
+)
+
+// parseStructStatement parses struct Point { x, y }, which declares the
+// variable Point holding the struct type.
+func (p *Parser) parseStructStatement() *ast.VarStatement {
+	def := &ast.StructDefinition{Token: p.curToken, Fields: []*ast.Identifier{}}
+
+	if !p.expectPeek(token.Ident) {
+		return nil
+	}
+
+	def.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.LBrace) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBrace) {
+		if !p.expectPeek(token.Ident) {
+			return nil
+		}
+
+		field := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		p.checkDuplicateMember(def.Fields, field, "field")
+		def.Fields = append(def.Fields, field)
+
+		if !p.peekTokenIs(token.RBrace) && !p.expectPeek(token.Comma) {
+			return nil
+		}
+	}
+
+	p.nextToken()
+	def.Rbrace = p.curToken
+
+	if p.peekTokenIs(token.Semicolon) {
+		p.nextToken()
+	}
+
+	return &ast.VarStatement{Token: def.Token, Name: def.Name, Value: def}
+}
+
+// parseStructLiteral parses the fields of Point{x: 1, y: 2}, the brace being
+// the current token.
+func (p *Parser) parseStructLiteral(structExp ast.Expression) ast.Expression {
+	defer p.controlClause(false)()
+
+	lit := &ast.StructLiteral{Token: p.curToken, Struct: structExp, Fields: []*ast.Identifier{}}
+
+	for !p.peekTokenIs(token.RBrace) {
+		if !p.expectPeek(token.Ident) {
+			return nil
+		}
+
+		field := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		p.checkDuplicateMember(lit.Fields, field, "field")
+
+		if !p.expectPeek(token.Colon) {
+			return nil
+		}
+
+		p.nextToken()
+
+		lit.Fields = append(lit.Fields, field)
+		lit.Values = append(lit.Values, p.parseExpression(LOWEST))
+
+		if !p.peekTokenIs(token.RBrace) && !p.expectPeek(token.Comma) {
+			return nil
+		}
+	}
+
+	p.nextToken()
+	lit.Rbrace = p.curToken
+
+	return lit
+}
+
+// checkDuplicateMember reports a field or method named like one before it.
+func (p *Parser) checkDuplicateMember(members []*ast.Identifier, member *ast.Identifier, kind string) {
+	for _, m := range members {
+		if m.Value == member.Value {
+			p.report(diagnostic.New(diagnostic.DuplicateMember, tokenSpan(member.Token),
+				"duplicate %s %s", kind, member.Value))
+		}
+	}
+}
+
+// controlClause sets whether the expressions parsed until the returned
+// function is called are in the header of an if, while or for statement.
+// There a brace after an identifier opens the body of the statement rather
+// than a struct literal, unless it is nested in brackets.
+func (p *Parser) controlClause(inside bool) (restore func()) {
+	outer := p.inControlClause
+	p.inControlClause = inside
+
+	return func() { p.inControlClause = outer }
+}
@@ -8,11 +8,20 @@ import (
 func (p *Parser) parseVarStatement() *ast.VarStatement {
 	stmt := &ast.VarStatement{Token: p.curToken}
 
-	if !p.expectPeek(token.Ident) {
+	switch {
+	case p.peekTokenIs(token.LBracket), p.peekTokenIs(token.LBrace):
+		p.nextToken()
+
+		if stmt.Pattern = p.parseBindingPattern(); stmt.Pattern == nil {
+			return nil
+		}
+
+	case !p.expectPeek(token.Ident):
 		return nil
-	}
 
-	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	default:
+		stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
 
 	if !p.expectPeek(token.Assign) {
 		return nil
@@ -5,10 +5,15 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sunbird/internal/ast"
+	"sunbird/internal/compiler"
+	"sunbird/internal/diagnostic"
 	"sunbird/internal/evaluator"
 	"sunbird/internal/lexer"
 	"sunbird/internal/object"
 	"sunbird/internal/parser"
+	"sunbird/internal/resolver"
+	"sunbird/internal/vm"
 
 	"github.com/peterh/liner"
 )
@@ -17,7 +22,53 @@ const PROMPT = "$ "
 
 var history_fn = filepath.Join(os.TempDir(), ".sunbird-history")
 
-func Start(in io.Reader, out io.Writer) {
+// session runs the lines typed in, keeping the variables they declare for
+// the next ones.
+type session interface {
+	eval(program *ast.Program) object.Object
+}
+
+type treeSession struct {
+	env *object.Environment
+}
+
+func (s *treeSession) eval(program *ast.Program) object.Object {
+	return evaluator.Eval(program, s.env)
+}
+
+type vmSession struct {
+	symbols   *compiler.SymbolTable
+	constants []object.Object
+	globals   []object.Object
+	runtime   *object.Runtime
+}
+
+func (s *vmSession) eval(program *ast.Program) object.Object {
+	declared := s.symbols.Declared()
+
+	c := compiler.NewWithState(s.symbols, s.constants)
+	if err := c.Compile(program); err != nil {
+		s.symbols.Forget(declared)
+		return err
+	}
+
+	bytecode := c.Bytecode()
+	s.constants = bytecode.Constants
+
+	result := vm.NewWithGlobals(bytecode, s.runtime, s.globals).Run()
+
+	// like the resolver, forget what a line that fails declared
+	if _, ok := result.(*object.Error); ok {
+		for _, symbol := range s.symbols.Forget(declared) {
+			vm.Undefine(s.globals, symbol.Index)
+		}
+	}
+
+	return result
+}
+
+// Start runs the REPL with the engine named tree or vm.
+func Start(in io.Reader, out io.Writer, engine string) {
 	line := liner.NewLiner()
 	defer line.Close()
 
@@ -27,9 +78,21 @@ func Start(in io.Reader, out io.Writer) {
 
 	setupCompleter(line)
 
-	env := object.NewEnvironment()
+	runtime := object.NewRuntime()
+	runtime.Stdin = in
+	runtime.Stdout = out
+
+	var s session = &treeSession{env: object.NewModuleEnvironment(runtime)}
+	if engine == "vm" {
+		s = &vmSession{
+			symbols:   compiler.NewSymbolTable(),
+			constants: []object.Object{},
+			globals:   vm.NewGlobals(),
+			runtime:   runtime,
+		}
+	}
 
-	replLoop(line, env, out)
+	replLoop(line, resolver.New(evaluator.IsBuiltin), s, out)
 }
 
 func loadHistory(line *liner.State) {
@@ -47,7 +110,12 @@ func saveHistory(line *liner.State) {
 }
 
 func setupCompleter(line *liner.State) {
-	keywords := []string{"func", "var", "true", "false", "if", "else", "return", "null"}
+	keywords := []string{
+		"func", "var", "true", "false", "if", "else", "return", "null",
+		"for", "in", "while", "break", "continue", "throw", "try", "catch",
+		"finally", "import", "export", "struct", "class", "extends", "super",
+		"instanceof", "match",
+	}
 
 	line.SetCompleter(func(input string) []string {
 		var completions []string
@@ -60,7 +128,7 @@ func setupCompleter(line *liner.State) {
 	})
 }
 
-func replLoop(line *liner.State, env *object.Environment, out io.Writer) {
+func replLoop(line *liner.State, r *resolver.Resolver, s session, out io.Writer) {
 	for {
 		input, err := line.Prompt(PROMPT)
 		if err != nil && err == liner.ErrPromptAborted {
@@ -77,29 +145,42 @@ func replLoop(line *liner.State, env *object.Environment, out io.Writer) {
 
 		line.AppendHistory(input)
 
-		evalInput(input, env, out)
+		evalInput(input, r, s, out)
 	}
 }
 
-func evalInput(input string, env *object.Environment, out io.Writer) {
-	l := lexer.New(input)
+func evalInput(input string, r *resolver.Resolver, s session, out io.Writer) {
+	l := lexer.New("", input)
 	p := parser.New(l)
 	program := p.ParseProgram()
 
-	if len(p.Errors()) != 0 {
-		printParserErrors(out, p.Errors())
+	if len(p.Diagnostics()) != 0 {
+		printDiagnostics(out, input, p.Diagnostics())
+		return
+	}
+
+	if errs := r.Resolve(program); len(errs) != 0 {
+		printDiagnostics(out, input, r.Diagnostics())
+		return
+	}
+
+	evaluated := s.eval(program)
+
+	if err, ok := evaluated.(*object.Error); ok {
+		// like a line with a resolver error, a line that fails declares nothing
+		r.Rollback()
+		printDiagnostics(out, input, []diagnostic.Diagnostic{err.Diagnostic()})
 		return
 	}
 
-	evaluated := evaluator.Eval(program, env)
 	if evaluated != nil {
 		io.WriteString(out, evaluated.Inspect())
 		io.WriteString(out, "\n")
 	}
 }
 
-func printParserErrors(out io.Writer, errors []string) {
-	for _, msg := range errors {
-		io.WriteString(out, "\t"+msg+"\n")
-	}
+func printDiagnostics(out io.Writer, input string, diagnostics []diagnostic.Diagnostic) {
+	renderer := diagnostic.NewRenderer(out, diagnostic.UseColor(out))
+	renderer.AddFile("", input)
+	renderer.Render(diagnostics)
 }
@@ -0,0 +1,502 @@
+package resolver
+
+import (
+	"fmt"
+	"sunbird/internal/ast"
+	"sunbird/internal/diagnostic"
+	"sunbird/internal/module"
+	"sunbird/internal/object"
+	"sunbird/internal/token"
+)
+
+// Resolver binds every identifier of a program to the slot its variable has
+// in the environments of the evaluator, and reports the variables that are
+// used without being declared, used before their declaration or declared
+// twice.
+type Resolver struct {
+	globals   *scope
+	current   *scope
+	isBuiltin func(name string) bool
+
+	// every error is reported both as a runtime error, raised when a module
+	// fails to load, and as a diagnostic
+	errors      []*object.Error
+	diagnostics []diagnostic.Diagnostic
+
+	// the globals before the last program resolved, for Rollback
+	slots    map[string]int
+	declared map[string]bool
+}
+
+// New creates a resolver for a module, whose programs may use the builtins
+// isBuiltin reports.
+func New(isBuiltin func(name string) bool) *Resolver {
+	return &Resolver{globals: newScope(nil, true), isBuiltin: isBuiltin}
+}
+
+// Resolve resolves a program in the global scope, which keeps the globals of
+// the programs resolved before, like earlier lines of the REPL. Nothing a
+// program with errors declares is kept.
+func (r *Resolver) Resolve(program *ast.Program) []*object.Error {
+	r.slots = make(map[string]int, len(r.globals.slots))
+	for name, slot := range r.globals.slots {
+		r.slots[name] = slot
+	}
+
+	r.declared = r.globals.copyDeclared()
+
+	r.current = r.globals
+	r.errors = nil
+	r.diagnostics = nil
+
+	r.hoist(program.Statements)
+	r.resolveStatements(program.Statements)
+
+	if len(r.errors) != 0 {
+		r.Rollback()
+	}
+
+	return r.errors
+}
+
+// Rollback forgets the globals declared by the last program resolved, when
+// running it failed.
+func (r *Resolver) Rollback() {
+	r.globals.slots = r.slots
+	r.globals.declared = r.declared
+}
+
+// DeclareGlobal declares a global set by the host rather than by a program,
+// returning its slot.
+func (r *Resolver) DeclareGlobal(name string) int {
+	r.globals.declared[name] = true
+	return r.globals.slot(name)
+}
+
+// Global returns the slot of a declared global.
+func (r *Resolver) Global(name string) (int, bool) {
+	if !r.globals.declared[name] {
+		return 0, false
+	}
+
+	return r.globals.slots[name], true
+}
+
+func (r *Resolver) resolve(node ast.Node) {
+	ast.Inspect(node, r.visit)
+}
+
+func (r *Resolver) resolveStatements(stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		r.resolve(stmt)
+	}
+}
+
+// visit resolves the nodes that refer to or declare variables, leaving the
+// others to ast.Inspect.
+func (r *Resolver) visit(node ast.Node) bool {
+	switch node := node.(type) {
+	case *ast.Identifier:
+		r.reference(node, node, "identifier not found: %s")
+
+	case *ast.MemberExpression:
+		r.resolve(node.Object)
+
+	// field names are not variables
+	case *ast.StructDefinition:
+
+	case *ast.StructLiteral:
+		r.resolve(node.Struct)
+		for _, value := range node.Values {
+			r.resolve(value)
+		}
+
+	case *ast.ClassDefinition:
+		r.resolveClassDefinition(node)
+
+	case *ast.SuperExpression:
+		if _, _, _, ok := r.lookup("super"); !ok {
+			r.errorAt(node, diagnostic.InvalidSuper, object.NameError, "super can only be used in the methods of a subclass")
+			break
+		}
+
+		r.reference(node.Class, node, "identifier not found: %s")
+		r.reference(node.Self, node, "identifier not found: %s")
+
+	case *ast.VarStatement:
+		r.resolve(node.Value)
+
+		if node.Pattern == nil {
+			r.declare(node.Name, node)
+			break
+		}
+
+		// a pattern declaring a name twice is reported at the second one
+		for _, name := range node.Names() {
+			r.declare(name, name)
+		}
+
+	case *ast.AssignStatement:
+		if target, ok := node.Target.(*ast.Identifier); ok {
+			r.reference(target, node, "Identifier '%s' has not been declared.")
+		} else {
+			r.resolve(node.Target)
+		}
+
+		r.resolve(node.Value)
+
+	case *ast.ImportStatement:
+		r.resolveImportStatement(node)
+
+	case *ast.IfExpression:
+		r.resolveIfExpression(node)
+
+	case *ast.FunctionLiteral:
+		r.enterScope(true)
+		for _, param := range node.Parameters {
+			r.bindPattern(param)
+		}
+
+		r.resolveBlock(node.Body)
+		r.leaveScope()
+
+	case *ast.MatchExpression:
+		r.resolve(node.Subject)
+
+		for _, arm := range node.Arms {
+			r.enterScope(false)
+			for _, pattern := range arm.Patterns {
+				r.bindPattern(pattern)
+			}
+
+			if arm.Guard != nil {
+				r.resolve(arm.Guard)
+			}
+
+			r.resolveBlock(arm.Body)
+			r.leaveScope()
+		}
+
+	case *ast.WhileStatement:
+		r.resolve(node.Condition)
+		r.resolveLoopBody(node.Body)
+
+	case *ast.ForStatement:
+		r.resolveForStatement(node)
+
+	case *ast.ForInStatement:
+		r.resolve(node.Iterable)
+
+		r.enterScope(false)
+		if node.Index != nil {
+			r.define(node.Index)
+		}
+
+		r.define(node.Item)
+		r.resolveBlock(node.Body)
+		r.leaveScope()
+
+	case *ast.TryStatement:
+		r.resolveStatements(node.Block.Statements)
+
+		if node.Catch != nil {
+			r.enterScope(false)
+			r.define(node.CatchParam)
+			r.resolveBlock(node.Catch)
+			r.leaveScope()
+		}
+
+		if node.Finally != nil {
+			r.resolveStatements(node.Finally.Statements)
+		}
+
+	default:
+		return true
+	}
+
+	return false
+}
+
+func (r *Resolver) resolveIfExpression(node *ast.IfExpression) {
+	r.resolve(node.Condition)
+
+	// a variable may be declared in both branches, since only one runs
+	before := r.current.copyDeclared()
+
+	r.resolveStatements(node.Consequence.Statements)
+
+	if node.Alternative != nil {
+		consequence := r.current.declared
+		r.current.declared = before
+
+		r.resolveStatements(node.Alternative.Statements)
+
+		for name := range consequence {
+			r.current.declared[name] = true
+		}
+	}
+}
+
+// resolveClassDefinition resolves the methods of a class in a scope holding
+// the superclass as super. Every method has self in its first slot.
+func (r *Resolver) resolveClassDefinition(node *ast.ClassDefinition) {
+	if node.Superclass != nil {
+		r.resolve(node.Superclass)
+	}
+
+	r.enterScope(false)
+	defer r.leaveScope()
+
+	if node.Superclass != nil {
+		r.defineImplicit("super")
+	}
+
+	for _, method := range node.Methods {
+		r.enterScope(true)
+		r.defineImplicit("self")
+
+		for _, param := range method.Parameters {
+			r.bindPattern(param)
+		}
+
+		r.resolveBlock(method.Body)
+		r.leaveScope()
+	}
+}
+
+func (r *Resolver) resolveForStatement(node *ast.ForStatement) {
+	r.enterScope(false)
+	defer r.leaveScope()
+
+	if node.Init != nil {
+		// like the evaluator, assigning to an unknown variable declares it
+		if assign, ok := node.Init.(*ast.AssignStatement); ok {
+			if ident, ok := assign.Target.(*ast.Identifier); ok {
+				if _, _, _, found := r.lookup(ident.Value); !found {
+					r.current.slot(ident.Value)
+					r.current.declared[ident.Value] = true
+				}
+			}
+		}
+
+		r.resolve(node.Init)
+	}
+
+	if node.Condition != nil {
+		r.resolve(node.Condition)
+	}
+
+	r.resolveLoopBody(node.Body)
+
+	if node.Update != nil {
+		r.resolve(node.Update)
+	}
+}
+
+func (r *Resolver) resolveImportStatement(node *ast.ImportStatement) {
+	if node.Names != nil {
+		for _, name := range node.Names {
+			r.declare(name, node)
+		}
+
+		return
+	}
+
+	node.Module = node.Alias
+	if node.Module == nil {
+		name := module.Name(node.Path.Value)
+		if !token.IsIdentifier(name) {
+			r.errorAt(node, diagnostic.InvalidModuleName, object.ImportError,
+				"module name %q is not an identifier, use import %q as name", name, node.Path.Value)
+			return
+		}
+
+		node.Module = &ast.Identifier{Token: node.Token, Value: name}
+	}
+
+	r.declare(node.Module, node)
+}
+
+// resolveLoopBody resolves a loop body, which gets an environment of its
+// own for every iteration.
+func (r *Resolver) resolveLoopBody(body *ast.BlockStatement) {
+	r.enterScope(false)
+	r.resolveBlock(body)
+	r.leaveScope()
+}
+
+// resolveBlock resolves the statements of a block that starts a scope.
+func (r *Resolver) resolveBlock(block *ast.BlockStatement) {
+	r.hoist(block.Statements)
+	r.resolveStatements(block.Statements)
+}
+
+func (r *Resolver) enterScope(function bool) {
+	r.current = newScope(r.current, function)
+}
+
+func (r *Resolver) leaveScope() {
+	r.current = r.current.outer
+}
+
+// reference binds an identifier that uses a variable. A variable of the
+// current function must be declared before it is used, one of an enclosing
+// function only before the function is called, which the evaluator checks.
+func (r *Resolver) reference(ident *ast.Identifier, node ast.Node, undeclared string) {
+	s, depth, sameFunction, ok := r.lookup(ident.Value)
+
+	switch {
+	case ok:
+		if sameFunction && !s.declared[ident.Value] {
+			r.errorAt(node, diagnostic.UsedBeforeDeclaration, object.NameError, "Identifier '%s' is used before its declaration.", ident.Value)
+		}
+
+		ident.Binding = ast.Binding{Kind: ast.Variable, Depth: depth, Slot: s.slots[ident.Value]}
+
+	case r.isBuiltin(ident.Value):
+		ident.Binding = ast.Binding{Kind: ast.Builtin}
+
+	default:
+		r.errorAt(node, diagnostic.UndeclaredVariable, object.NameError, undeclared, ident.Value)
+	}
+}
+
+// lookup finds the innermost scope with a variable called name, how many
+// scopes out it is and whether it belongs to the current function.
+func (r *Resolver) lookup(name string) (*scope, int, bool, bool) {
+	sameFunction := true
+	depth := 0
+
+	for s := r.current; s != nil; s = s.outer {
+		if _, ok := s.slots[name]; ok {
+			return s, depth, sameFunction, true
+		}
+
+		if s.function {
+			sameFunction = false
+		}
+
+		depth++
+	}
+
+	return nil, 0, false, false
+}
+
+// declare declares a variable like a var statement does: its name must not
+// be visible already, even from an enclosing scope.
+func (r *Resolver) declare(ident *ast.Identifier, node ast.Node) {
+	for s := r.current; s != nil; s = s.outer {
+		if s.declared[ident.Value] {
+			r.errorAt(node, diagnostic.DuplicateDeclaration, object.NameError, "Identifier '%s' has already been declared.", ident.Value)
+			break
+		}
+	}
+
+	r.define(ident)
+}
+
+// define declares a variable that may shadow those of enclosing scopes, like
+// a parameter.
+func (r *Resolver) define(ident *ast.Identifier) {
+	r.current.declared[ident.Value] = true
+	ident.Binding = ast.Binding{Kind: ast.Variable, Slot: r.current.slot(ident.Value)}
+}
+
+// bindPattern declares the variables a pattern binds, which may shadow those
+// of enclosing scopes but not each other.
+func (r *Resolver) bindPattern(pattern ast.Pattern) {
+	ast.Inspect(pattern, func(node ast.Node) bool {
+		ident, ok := node.(*ast.Identifier)
+		if !ok {
+			return true
+		}
+
+		if r.current.declared[ident.Value] {
+			r.errorAt(ident, diagnostic.DuplicateDeclaration, object.NameError, "Identifier '%s' has already been declared.", ident.Value)
+		}
+
+		r.define(ident)
+
+		return false
+	})
+}
+
+// defineImplicit declares a variable that no identifier declares, like self
+// in methods.
+func (r *Resolver) defineImplicit(name string) {
+	r.current.declared[name] = true
+	r.current.slot(name)
+}
+
+// hoist reserves the slots of the variables declared in a scope before
+// resolving it, so that functions can refer to variables declared after
+// them.
+func (r *Resolver) hoist(stmts []ast.Statement) {
+	var visit func(node ast.Node) bool
+
+	visit = func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.VarStatement:
+			for _, name := range node.Names() {
+				r.current.slot(name.Value)
+			}
+
+		case *ast.ImportStatement:
+			for _, name := range node.Names {
+				r.current.slot(name.Value)
+			}
+
+			if node.Alias != nil {
+				r.current.slot(node.Alias.Value)
+			} else if node.Names == nil {
+				r.current.slot(module.Name(node.Path.Value))
+			}
+
+			return false
+
+		// these start scopes of their own, except for the parts that run in
+		// the enclosing one
+		case *ast.FunctionLiteral, *ast.ForStatement:
+			return false
+
+		case *ast.WhileStatement:
+			ast.Inspect(node.Condition, visit)
+			return false
+
+		case *ast.MatchExpression:
+			ast.Inspect(node.Subject, visit)
+			return false
+
+		case *ast.ForInStatement:
+			ast.Inspect(node.Iterable, visit)
+			return false
+
+		case *ast.TryStatement:
+			ast.Inspect(node.Block, visit)
+			if node.Finally != nil {
+				ast.Inspect(node.Finally, visit)
+			}
+
+			return false
+		}
+
+		return true
+	}
+
+	for _, stmt := range stmts {
+		ast.Inspect(stmt, visit)
+	}
+}
+
+// Diagnostics returns the errors of the last program resolved as diagnostics.
+func (r *Resolver) Diagnostics() []diagnostic.Diagnostic {
+	return r.diagnostics
+}
+
+func (r *Resolver) errorAt(node ast.Node, code diagnostic.Code, kind, format string, a ...interface{}) {
+	message := fmt.Sprintf(format, a...)
+	span := diagnostic.Span{Start: node.Pos(), End: node.End()}
+
+	r.errors = append(r.errors, &object.Error{Message: message, Kind: kind, Pos: span.Start, End: span.End})
+	r.diagnostics = append(r.diagnostics, diagnostic.New(code, span, "%s: %s", kind, message))
+}
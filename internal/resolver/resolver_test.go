@@ -0,0 +1,211 @@
+package resolver_test
+
+import (
+	"fmt"
+	"sunbird/internal/ast"
+	"sunbird/internal/diagnostic"
+	"sunbird/internal/evaluator"
+	"sunbird/internal/lexer"
+	"sunbird/internal/parser"
+	"sunbird/internal/resolver"
+	"testing"
+)
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New("", input))
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %q", p.Errors())
+	}
+
+	return program
+}
+
+func TestResolveErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"var x = 1; x + len([])", nil},
+		{"var f = func() { g() }; var g = func() { 1 }", nil},
+		{"var f = func(n) { if n > 0 { f(n - 1) } }", nil},
+		{"if true { var y = 1 } else { var y = 2 }; y", nil},
+		{"var x = 1; for x in [1] { x }; try { 1 } catch (x) { x }", nil},
+		{"for i = 0; i < 3; i += 1 { i }", nil},
+		{"foo", []string{"identifier not found: foo"}},
+		{"x = 1", []string{"Identifier 'x' has not been declared."}},
+		{"var a = 1; var a = 2", []string{"Identifier 'a' has already been declared."}},
+		{"if true { var y = 1 }; var y = 2", []string{"Identifier 'y' has already been declared."}},
+		{"var x = 1; var f = func() { var x = 2 }", []string{"Identifier 'x' has already been declared."}},
+		{"x; var x = 1", []string{"Identifier 'x' is used before its declaration."}},
+		{"var x = x + 1", []string{"Identifier 'x' is used before its declaration."}},
+		{"while true { n = 1; var n = 0 }", []string{"Identifier 'n' is used before its declaration."}},
+		{"a; b", []string{"identifier not found: a", "identifier not found: b"}},
+		{"class A { f() { self } }; class B extends A { f() { func() { super.f() } } }", nil},
+		{"self", []string{"identifier not found: self"}},
+		{"class A { f() { super.f() } }", []string{"super can only be used in the methods of a subclass"}},
+		{"var x = 1; match x { [x, y] if x > y => x, x => x }", nil},
+		{"match 1 { [x, x] => x }", []string{"Identifier 'x' has already been declared."}},
+		{"match 1 { x => 1 }; x", []string{"identifier not found: x"}},
+		{"var [a, {b, c: [d]}, ...rest] = x; a + b + d + rest", []string{"identifier not found: x"}},
+		{"var f = func([a, b], {c}) { a + b + c }", nil},
+		{"var [a, a] = [1, 2]", []string{"Identifier 'a' has already been declared."}},
+		{"var a = 1; var {a} = {}", []string{"Identifier 'a' has already been declared."}},
+		{"var f = func([a], a) { a }", []string{"Identifier 'a' has already been declared."}},
+		{"var f = func() { [x, y] }; var [x, y] = [1, 2]", nil},
+		{"class A extends B {}; var B = 1", []string{"Identifier 'B' is used before its declaration."}},
+		{`import "./my-module"`, []string{`module name "my-module" is not an identifier, use import "./my-module" as name`}},
+	}
+
+	for _, tt := range tests {
+		errs := resolver.New(evaluator.IsBuiltin).Resolve(parse(t, tt.input))
+
+		if len(errs) != len(tt.expected) {
+			t.Errorf("%s: wrong number of errors. expected=%d, got=%d (%v)", tt.input, len(tt.expected), len(errs), errs)
+			continue
+		}
+
+		for i, err := range errs {
+			if err.Message != tt.expected[i] {
+				t.Errorf("%s: wrong error. expected=%q, got=%q", tt.input, tt.expected[i], err.Message)
+			}
+		}
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected diagnostic.Code
+		message  string
+	}{
+		{"foo", diagnostic.UndeclaredVariable, "NameError: identifier not found: foo"},
+		{"x; var x = 1", diagnostic.UsedBeforeDeclaration, "NameError: Identifier 'x' is used before its declaration."},
+		{"var a = 1; var a = 2", diagnostic.DuplicateDeclaration, "NameError: Identifier 'a' has already been declared."},
+		{"class A { f() { super.f() } }", diagnostic.InvalidSuper, "NameError: super can only be used in the methods of a subclass"},
+		{`import "./my-module"`, diagnostic.InvalidModuleName, `ImportError: module name "my-module" is not an identifier, use import "./my-module" as name`},
+	}
+
+	for _, tt := range tests {
+		r := resolver.New(evaluator.IsBuiltin)
+		r.Resolve(parse(t, tt.input))
+
+		diagnostics := r.Diagnostics()
+		if len(diagnostics) != 1 {
+			t.Errorf("%s: expected 1 diagnostic, got=%d", tt.input, len(diagnostics))
+			continue
+		}
+
+		if diagnostics[0].Code != tt.expected || diagnostics[0].Message != tt.message {
+			t.Errorf("%s: wrong diagnostic. expected=%s %q, got=%s %q",
+				tt.input, tt.expected, tt.message, diagnostics[0].Code, diagnostics[0].Message)
+		}
+	}
+}
+
+func TestErrorPositions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"var a = 1; var a = 2", "1:12"},
+		{"var [a, a] = [1, 2]", "1:9"},
+		{"var a = 1\nvar {b, c: [a]} = {}", "2:13"},
+		{"x; var x = 1", "1:1"},
+	}
+
+	for _, tt := range tests {
+		errs := resolver.New(evaluator.IsBuiltin).Resolve(parse(t, tt.input))
+		if len(errs) == 0 {
+			t.Errorf("%s: expected an error", tt.input)
+			continue
+		}
+
+		if pos := fmt.Sprintf("%d:%d", errs[0].Pos.Line, errs[0].Pos.Col); pos != tt.expected {
+			t.Errorf("%s: wrong position. expected=%s, got=%s", tt.input, tt.expected, pos)
+		}
+	}
+}
+
+func TestBindings(t *testing.T) {
+	program := parse(t, `var a = 1
+var f = func(x) {
+  while true { var b = x + a }
+}
+len`)
+
+	var idents []*ast.Identifier
+
+	ast.Inspect(program, func(node ast.Node) bool {
+		if ident, ok := node.(*ast.Identifier); ok {
+			idents = append(idents, ident)
+		}
+
+		return true
+	})
+
+	if errs := resolver.New(evaluator.IsBuiltin).Resolve(program); len(errs) != 0 {
+		t.Fatalf("resolve errors: %v", errs)
+	}
+
+	expected := map[string]ast.Binding{
+		"f":   {Kind: ast.Variable, Depth: 0, Slot: 1},
+		"b":   {Kind: ast.Variable, Depth: 0, Slot: 0},
+		"len": {Kind: ast.Builtin},
+	}
+
+	for _, ident := range idents {
+		want, ok := expected[ident.Value]
+		if !ok {
+			continue
+		}
+
+		if ident.Binding != want {
+			t.Errorf("%s at %d:%d: wrong binding. expected=%+v, got=%+v",
+				ident.Value, ident.Pos().Line, ident.Pos().Col, want, ident.Binding)
+		}
+	}
+
+	// x + a in the loop body, one and two scopes out of it
+	var sum *ast.InfixExpression
+
+	ast.Inspect(program, func(node ast.Node) bool {
+		if infix, ok := node.(*ast.InfixExpression); ok {
+			sum = infix
+		}
+
+		return true
+	})
+
+	x := sum.Left.(*ast.Identifier).Binding
+	if x != (ast.Binding{Kind: ast.Variable, Depth: 1, Slot: 0}) {
+		t.Errorf("wrong binding for x. got=%+v", x)
+	}
+
+	a := sum.Right.(*ast.Identifier).Binding
+	if a != (ast.Binding{Kind: ast.Variable, Depth: 2, Slot: 0}) {
+		t.Errorf("wrong binding for a. got=%+v", a)
+	}
+}
+
+func TestResolveKeepsGlobals(t *testing.T) {
+	r := resolver.New(evaluator.IsBuiltin)
+
+	if errs := r.Resolve(parse(t, "var x = 1")); len(errs) != 0 {
+		t.Fatalf("resolve errors: %v", errs)
+	}
+
+	if errs := r.Resolve(parse(t, "var y = missing")); len(errs) != 1 {
+		t.Fatalf("expected an error, got %v", errs)
+	}
+
+	// y was dropped with the line that failed
+	if errs := r.Resolve(parse(t, "var y = x")); len(errs) != 0 {
+		t.Errorf("resolve errors: %v", errs)
+	}
+
+	if errs := r.Resolve(parse(t, "var x = 2")); len(errs) != 1 {
+		t.Errorf("expected x to be declared already, got %v", errs)
+	}
+}
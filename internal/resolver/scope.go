@@ -0,0 +1,38 @@
+package resolver
+
+// scope is an environment the evaluator creates: the top level of the
+// program, a function call, a loop iteration or a catch block.
+type scope struct {
+	outer    *scope
+	function bool // the top level of a function or of the program
+
+	slots map[string]int // every variable of the scope, including hoisted ones
+
+	// declared holds the variables whose declaration was reached, on the
+	// current path through if and else blocks
+	declared map[string]bool
+}
+
+func newScope(outer *scope, function bool) *scope {
+	return &scope{outer: outer, function: function, slots: map[string]int{}, declared: map[string]bool{}}
+}
+
+// slot returns the slot of name, reserving a new one the first time.
+func (s *scope) slot(name string) int {
+	if slot, ok := s.slots[name]; ok {
+		return slot
+	}
+
+	s.slots[name] = len(s.slots)
+
+	return s.slots[name]
+}
+
+func (s *scope) copyDeclared() map[string]bool {
+	declared := make(map[string]bool, len(s.declared))
+	for name := range s.declared {
+		declared[name] = true
+	}
+
+	return declared
+}
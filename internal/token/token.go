@@ -6,7 +6,8 @@ type Token struct {
 	Type    TokenType
 	Literal string
 
-	Pos Position
+	Pos Position // position of the first char of the token
+	End Position // position immediately after the token
 }
 
 const (
@@ -19,15 +20,37 @@ const (
 	Int
 	String
 
+	// Interpolated strings, e.g. "a ${b} c ${d} e" is lexed as
+	// InterpStart("a "), b, InterpMid(" c "), d, InterpEnd(" e")
+	InterpStart
+	InterpMid
+	InterpEnd
+
 	// Operators
 	Assign
+	PlusAssign
+	MinusAssign
+	AsteriskAssign
+	SlashAssign
+	PercentAssign
 	Plus
 	Minus
 	Bang
 	Asterisk
 	Slash
+	Percent
+	Power
+	FloorDiv
 	Pipe
 
+	// Bitwise operators
+	BitAnd
+	BitOr
+	BitXor
+	BitNot
+	ShiftLeft
+	ShiftRight
+
 	// Comparison operators
 	Eq
 	NotEq
@@ -45,6 +68,10 @@ const (
 	// Delimiter
 	Comma
 	Semicolon
+	Colon
+	Dot
+	Arrow
+	Ellipsis
 
 	LParen
 	RParen
@@ -64,6 +91,20 @@ const (
 	Null
 	For
 	While
+	Break
+	Continue
+	In
+	Throw
+	Try
+	Catch
+	Finally
+	Import
+	Export
+	Struct
+	Class
+	Super
+	InstanceOf
+	Match
 )
 
 func (tt TokenType) String() string {
@@ -80,8 +121,24 @@ func (tt TokenType) String() string {
 		return "INT"
 	case String:
 		return "STRING"
+	case InterpStart:
+		return "INTERP_START"
+	case InterpMid:
+		return "INTERP_MID"
+	case InterpEnd:
+		return "INTERP_END"
 	case Assign:
 		return "="
+	case PlusAssign:
+		return "+="
+	case MinusAssign:
+		return "-="
+	case AsteriskAssign:
+		return "*="
+	case SlashAssign:
+		return "/="
+	case PercentAssign:
+		return "%="
 	case Plus:
 		return "+"
 	case Minus:
@@ -92,8 +149,26 @@ func (tt TokenType) String() string {
 		return "*"
 	case Slash:
 		return "/"
+	case Percent:
+		return "%"
+	case Power:
+		return "**"
+	case FloorDiv:
+		return "~/"
 	case Pipe:
 		return "|>"
+	case BitAnd:
+		return "&"
+	case BitOr:
+		return "|"
+	case BitXor:
+		return "^"
+	case BitNot:
+		return "~"
+	case ShiftLeft:
+		return "<<"
+	case ShiftRight:
+		return ">>"
 	case Eq:
 		return "=="
 	case NotEq:
@@ -114,6 +189,14 @@ func (tt TokenType) String() string {
 		return ","
 	case Semicolon:
 		return ";"
+	case Colon:
+		return ":"
+	case Dot:
+		return "."
+	case Arrow:
+		return "=>"
+	case Ellipsis:
+		return "..."
 	case LParen:
 		return "("
 	case RParen:
@@ -146,22 +229,64 @@ func (tt TokenType) String() string {
 		return "FOR"
 	case While:
 		return "WHILE"
+	case Break:
+		return "BREAK"
+	case Continue:
+		return "CONTINUE"
+	case In:
+		return "IN"
+	case Throw:
+		return "THROW"
+	case Try:
+		return "TRY"
+	case Catch:
+		return "CATCH"
+	case Finally:
+		return "FINALLY"
+	case Import:
+		return "IMPORT"
+	case Export:
+		return "EXPORT"
+	case Struct:
+		return "STRUCT"
+	case Class:
+		return "CLASS"
+	case Super:
+		return "SUPER"
+	case InstanceOf:
+		return "INSTANCEOF"
+	case Match:
+		return "MATCH"
 	default:
 		return "UNKNOWN"
 	}
 }
 
 var keywords = map[string]TokenType{
-	"func":   Function,
-	"var":    Var,
-	"true":   True,
-	"false":  False,
-	"if":     If,
-	"else":   Else,
-	"return": Return,
-	"null":   Null,
-	"for":    For,
-	"while":  While,
+	"func":       Function,
+	"var":        Var,
+	"true":       True,
+	"false":      False,
+	"if":         If,
+	"else":       Else,
+	"return":     Return,
+	"null":       Null,
+	"for":        For,
+	"while":      While,
+	"break":      Break,
+	"continue":   Continue,
+	"in":         In,
+	"throw":      Throw,
+	"try":        Try,
+	"catch":      Catch,
+	"finally":    Finally,
+	"import":     Import,
+	"export":     Export,
+	"struct":     Struct,
+	"class":      Class,
+	"super":      Super,
+	"instanceof": InstanceOf,
+	"match":      Match,
 }
 
 func LookupIdent(ident string) TokenType {
@@ -170,3 +295,19 @@ func LookupIdent(ident string) TokenType {
 	}
 	return Ident
 }
+
+// IsIdentifier reports whether s lexes as a single identifier.
+func IsIdentifier(s string) bool {
+	if s == "" || LookupIdent(s) != Ident {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if !('a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,210 @@
+package vm
+
+import (
+	"fmt"
+	"sunbird/internal/ast"
+	"sunbird/internal/evaluator"
+	"sunbird/internal/object"
+)
+
+// call calls fn, which is on the stack below its numArgs arguments.
+func (vm *VM) call(fn object.Object, numArgs int) *object.Error {
+	switch fn := fn.(type) {
+	case *object.Function:
+		if fn.Compiled != nil {
+			return vm.callFunction(fn, numArgs)
+		}
+
+	case *object.BoundMethod:
+		if fn.Method.Compiled != nil {
+			return vm.callMethod(fn.Method, fn.Self, numArgs)
+		}
+
+	case *object.Class:
+		return vm.construct(fn, numArgs)
+
+	case *object.Builtin:
+		args := make([]object.Object, numArgs)
+		copy(args, vm.stack[vm.sp-numArgs:vm.sp])
+		vm.sp -= numArgs + 1
+
+		result := fn.Fn(vm.runtime, args...)
+		if err := asError(result); err != nil {
+			return err
+		}
+
+		if err := vm.runtime.Allocate(result); err != nil {
+			return err
+		}
+
+		vm.push(result)
+
+		return nil
+	}
+
+	return typeError("not a function: %s", fn.Type())
+}
+
+func (vm *VM) callFunction(fn *object.Function, numArgs int) *object.Error {
+	compiled := fn.Compiled
+	if numArgs != compiled.NumParameters {
+		return typeError("wrong number of arguments: expected %d, got %d", compiled.NumParameters, numArgs)
+	}
+
+	// the main function of the program is not a call
+	if max := vm.runtime.Limits.MaxDepth; max > 0 && len(vm.frames) > max {
+		return &object.Error{Message: "stack overflow", Kind: object.RecursionError}
+	}
+
+	f := &frame{fn: fn, bp: vm.sp - numArgs}
+	if compiled.NumCells != 0 {
+		f.cells = make([]*object.Cell, compiled.NumCells)
+	}
+
+	for i := numArgs; i < compiled.NumLocals; i++ {
+		vm.push(undefined)
+	}
+
+	vm.frames = append(vm.frames, f)
+
+	return nil
+}
+
+// callMethod calls a method, passing self before its numArgs arguments.
+func (vm *VM) callMethod(method *object.Function, self object.Object, numArgs int) *object.Error {
+	if want := method.Compiled.NumParameters - 1; numArgs != want {
+		return typeError("wrong number of arguments: expected %d, got %d", want, numArgs)
+	}
+
+	vm.push(nil)
+
+	// the method replaces the callee below the arguments, which move up to
+	// make room for self
+	call := vm.stack[vm.sp-2-numArgs : vm.sp]
+	copy(call[2:], call[1:numArgs+1])
+	call[0] = method
+	call[1] = self
+
+	return vm.callFunction(method, numArgs+1)
+}
+
+// construct creates an instance of a class, calling its init method with the
+// numArgs arguments on the stack.
+func (vm *VM) construct(class *object.Class, numArgs int) *object.Error {
+	instance := object.NewClassInstance(class)
+
+	init, ok := class.Method("init")
+	if !ok {
+		if numArgs != 0 {
+			return typeError("wrong number of arguments: expected 0, got %d", numArgs)
+		}
+
+		vm.sp--
+		vm.push(instance)
+
+		return nil
+	}
+
+	if err := vm.callMethod(init, instance, numArgs); err != nil {
+		return err
+	}
+
+	vm.frames[len(vm.frames)-1].instance = instance
+
+	return nil
+}
+
+// popFrame returns from the innermost function, removing it and its
+// arguments from the stack.
+func (vm *VM) popFrame() {
+	f := vm.frames[len(vm.frames)-1]
+	vm.frames = vm.frames[:len(vm.frames)-1]
+	vm.sp = f.bp - 1
+
+	for len(vm.handlers) != 0 && vm.handlers[len(vm.handlers)-1].frame >= len(vm.frames) {
+		vm.handlers = vm.handlers[:len(vm.handlers)-1]
+	}
+}
+
+func (vm *VM) closure(f *frame, compiled *object.CompiledFunction) *object.Function {
+	free := make([]*object.Cell, len(compiled.Captures))
+
+	for i, capture := range compiled.Captures {
+		if capture.Free {
+			free[i] = f.fn.Free[capture.Index]
+			continue
+		}
+
+		// a variable declared after the closure in a nested block
+		if f.cells[capture.Index] == nil {
+			f.cells[capture.Index] = &object.Cell{Value: undefined}
+		}
+
+		free[i] = f.cells[capture.Index]
+	}
+
+	return &object.Function{
+		Parameters: compiled.Parameters,
+		Body:       compiled.Body,
+		Compiled:   compiled,
+		Free:       free,
+		Globals:    f.fn.Globals,
+	}
+}
+
+// recover unwinds the stack to the innermost try block handling err, adding
+// the functions it leaves to the stack trace of err like the evaluator does.
+// It returns false when err leaves the frame at index entry.
+func (vm *VM) recover(err *object.Error, entry int) bool {
+	for {
+		f := vm.frames[len(vm.frames)-1]
+
+		if !err.Pos.IsValid() {
+			if node := f.fn.Compiled.NodeAt(f.op); node != nil {
+				err.Pos = node.Pos()
+				err.End = node.End()
+			}
+		}
+
+		if n := len(vm.handlers); n != 0 && vm.handlers[n-1].frame == len(vm.frames)-1 && !err.Fatal {
+			h := vm.handlers[n-1]
+			vm.handlers = vm.handlers[:n-1]
+
+			vm.sp = h.sp
+			vm.push(&object.ErrorValue{Error: err})
+			f.ip = h.target
+
+			return true
+		}
+
+		if len(vm.frames)-1 == entry {
+			return false
+		}
+
+		vm.popFrame()
+
+		caller := vm.frames[len(vm.frames)-1]
+		call := caller.fn.Compiled.NodeAt(caller.op)
+		err.AddFrame(object.Frame{Function: evaluator.CalleeName(call), Pos: call.Pos()})
+	}
+}
+
+// nameError reports a missing variable, named by the node being executed.
+func (vm *VM) nameError(f *frame, format string) *object.Error {
+	var name string
+
+	switch node := f.fn.Compiled.NodeAt(f.op).(type) {
+	case *ast.Identifier:
+		name = node.Value
+	case *ast.VarStatement:
+		name = node.Name.Value
+	case *ast.AssignStatement:
+		name = node.Target.String()
+	}
+
+	return &object.Error{Message: fmt.Sprintf(format, name), Kind: object.NameError}
+}
+
+func typeError(format string, a ...interface{}) *object.Error {
+	return &object.Error{Message: fmt.Sprintf(format, a...), Kind: object.TypeError}
+}
@@ -0,0 +1,26 @@
+package vm
+
+import "sunbird/internal/object"
+
+// frame is a function call being executed.
+type frame struct {
+	fn    *object.Function
+	ip    int // the next instruction
+	op    int // the instruction being executed, for error locations
+	bp    int // the stack index of the first local
+	cells []*object.Cell
+
+	// the instance created by calling a class, which its init method returns
+	instance *object.ClassInstance
+}
+
+func (f *frame) instructions() []byte {
+	return f.fn.Compiled.Instructions
+}
+
+// handler is an active try block.
+type handler struct {
+	frame  int // the index of the frame it belongs to
+	target int // where the catch or finally code starts
+	sp     int // the stack height when the try block started
+}
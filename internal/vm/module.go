@@ -0,0 +1,42 @@
+package vm
+
+import (
+	"sunbird/internal/ast"
+	"sunbird/internal/compiler"
+	"sunbird/internal/evaluator"
+	"sunbird/internal/module"
+	"sunbird/internal/object"
+)
+
+// importModule returns the module imported as path by the file importer,
+// compiling and running it with a VM of its own unless an earlier import
+// already did.
+func (vm *VM) importModule(path, importer string, f *frame) object.Object {
+	node := f.fn.Compiled.NodeAt(f.op)
+
+	return module.Import(vm.runtime, path, importer, func(module *object.Module, program *ast.Program) *object.Error {
+		c := compiler.New()
+
+		err := evaluator.ResolveModule(program)
+		if err == nil {
+			err = c.Compile(program)
+		}
+
+		if err == nil {
+			bytecode := c.Bytecode()
+			moduleVM := New(bytecode, vm.runtime)
+
+			if err = asError(moduleVM.Run()); err == nil {
+				for name, index := range bytecode.Exports {
+					module.Exports[name] = moduleVM.globals[index]
+				}
+
+				return nil
+			}
+		}
+
+		err.AddFrame(object.Frame{Function: "<module " + module.Name + ">", Pos: node.Pos()})
+
+		return err
+	})
+}
@@ -0,0 +1,96 @@
+package vm
+
+import (
+	"sunbird/internal/code"
+	"sunbird/internal/evaluator"
+	"sunbird/internal/object"
+)
+
+var binaryOperators = map[code.Opcode]string{
+	code.OpAdd:          "+",
+	code.OpSub:          "-",
+	code.OpMul:          "*",
+	code.OpDiv:          "/",
+	code.OpMod:          "%",
+	code.OpFloorDiv:     "~/",
+	code.OpPow:          "**",
+	code.OpBitAnd:       "&",
+	code.OpBitOr:        "|",
+	code.OpBitXor:       "^",
+	code.OpShiftLeft:    "<<",
+	code.OpShiftRight:   ">>",
+	code.OpEqual:        "==",
+	code.OpNotEqual:     "!=",
+	code.OpLess:         "<",
+	code.OpGreater:      ">",
+	code.OpLessEqual:    "<=",
+	code.OpGreaterEqual: ">=",
+	code.OpInstanceOf:   "instanceof",
+}
+
+var prefixOperators = map[code.Opcode]string{
+	code.OpMinus:  "-",
+	code.OpNot:    "!",
+	code.OpBitNot: "~",
+}
+
+func (vm *VM) executeBinaryOperation(op code.Opcode) *object.Error {
+	right := vm.pop()
+	left := vm.pop()
+
+	// the common integer operations skip the generic implementation
+	if l, ok := left.(*object.Integer); ok {
+		if r, ok := right.(*object.Integer); ok {
+			if result := integerOperation(op, l.Value, r.Value); result != nil {
+				vm.push(result)
+				return nil
+			}
+		}
+	}
+
+	result := evaluator.Infix(binaryOperators[op], left, right)
+	if err := asError(result); err != nil {
+		return err
+	}
+
+	if err := vm.runtime.Allocate(result); err != nil {
+		return err
+	}
+
+	vm.push(result)
+
+	return nil
+}
+
+func integerOperation(op code.Opcode, left, right int64) object.Object {
+	switch op {
+	case code.OpAdd:
+		return &object.Integer{Value: left + right}
+	case code.OpSub:
+		return &object.Integer{Value: left - right}
+	case code.OpMul:
+		return &object.Integer{Value: left * right}
+	case code.OpEqual:
+		return nativeBool(left == right)
+	case code.OpNotEqual:
+		return nativeBool(left != right)
+	case code.OpLess:
+		return nativeBool(left < right)
+	case code.OpGreater:
+		return nativeBool(left > right)
+	case code.OpLessEqual:
+		return nativeBool(left <= right)
+	case code.OpGreaterEqual:
+		return nativeBool(left >= right)
+	}
+
+	return nil
+}
+
+func nativeBool(b bool) *object.Boolean {
+	if b {
+		return evaluator.TRUE
+	}
+
+	return evaluator.FALSE
+}
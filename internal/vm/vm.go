@@ -0,0 +1,533 @@
+package vm
+
+import (
+	"strings"
+	"sunbird/internal/ast"
+	"sunbird/internal/code"
+	"sunbird/internal/compiler"
+	"sunbird/internal/evaluator"
+	"sunbird/internal/object"
+)
+
+const (
+	StackSize   = 2048 // the initial size, the stack grows as needed
+	GlobalsSize = 65536
+)
+
+// undefined marks variables that have a slot but were not declared yet.
+var undefined object.Object = &undefinedValue{}
+
+// undefinedValue is never visible to programs. Unlike object.Null it is not
+// zero-sized, so its pointer cannot be equal to NULL.
+type undefinedValue struct{ _ byte }
+
+func (u *undefinedValue) Type() object.ObjectType { return object.NullObj }
+func (u *undefinedValue) Inspect() string         { return "undefined" }
+
+// VM runs the bytecode of a program.
+type VM struct {
+	main    *object.CompiledFunction
+	globals []object.Object
+	runtime *object.Runtime
+
+	stack []object.Object
+	sp    int // the next free slot, the top of the stack is stack[sp-1]
+
+	frames   []*frame
+	handlers []handler
+}
+
+func New(bytecode *compiler.Bytecode, runtime *object.Runtime) *VM {
+	return NewWithGlobals(bytecode, runtime, NewGlobals())
+}
+
+// NewWithGlobals creates a VM that keeps the globals of an earlier run, as
+// the REPL does for every line.
+func NewWithGlobals(bytecode *compiler.Bytecode, runtime *object.Runtime, globals []object.Object) *VM {
+	return &VM{
+		main:    bytecode.Main,
+		globals: globals,
+		runtime: runtime,
+		stack:   make([]object.Object, StackSize),
+	}
+}
+
+// NewGlobals returns the globals of a program that declared nothing yet.
+func NewGlobals() []object.Object {
+	globals := make([]object.Object, GlobalsSize)
+	for i := range globals {
+		globals[i] = undefined
+	}
+
+	return globals
+}
+
+// Undefine makes the global at index undeclared again, like before its var
+// statement ran.
+func Undefine(globals []object.Object, index int) {
+	globals[index] = undefined
+}
+
+// Run runs the program, returning the value of its last expression
+// statement or an uncaught error.
+func (vm *VM) Run() object.Object {
+	main := &object.Function{Compiled: vm.main, Globals: vm.globals}
+
+	vm.sp = 0
+	vm.push(main)
+
+	if err := vm.callFunction(main, 0); err != nil {
+		return err
+	}
+
+	return vm.run(len(vm.frames) - 1)
+}
+
+// run executes instructions until the frame at index entry returns.
+func (vm *VM) run(entry int) object.Object {
+	for {
+		f := vm.frames[len(vm.frames)-1]
+		ins := f.instructions()
+
+		f.op = f.ip
+		op := code.Opcode(ins[f.ip])
+		f.ip++
+
+		var err *object.Error
+		if err = vm.runtime.Step(); err != nil {
+			// limit errors are not catchable, recover only records where
+			// they happened
+			vm.recover(err, entry)
+			return err
+		}
+
+		switch op {
+		case code.OpConstant:
+			vm.push(f.fn.Compiled.Constants[vm.readUint16(f)])
+
+		case code.OpNull:
+			vm.push(evaluator.NULL)
+
+		case code.OpTrue:
+			vm.push(evaluator.TRUE)
+
+		case code.OpFalse:
+			vm.push(evaluator.FALSE)
+
+		case code.OpPop:
+			vm.sp--
+
+		case code.OpDup:
+			vm.push(vm.stack[vm.sp-1])
+
+		case code.OpDup2:
+			vm.push(vm.stack[vm.sp-2])
+			vm.push(vm.stack[vm.sp-2])
+
+		case code.OpAdd, code.OpSub, code.OpMul, code.OpDiv, code.OpMod, code.OpFloorDiv, code.OpPow,
+			code.OpBitAnd, code.OpBitOr, code.OpBitXor, code.OpShiftLeft, code.OpShiftRight,
+			code.OpEqual, code.OpNotEqual, code.OpLess, code.OpGreater, code.OpLessEqual, code.OpGreaterEqual,
+			code.OpInstanceOf:
+			err = vm.executeBinaryOperation(op)
+
+		case code.OpMinus, code.OpNot, code.OpBitNot:
+			result := evaluator.Prefix(prefixOperators[op], vm.pop())
+			if err = asError(result); err == nil {
+				vm.push(result)
+			}
+
+		case code.OpJump:
+			f.ip = vm.readUint16(f)
+
+		case code.OpJumpNotTruthy:
+			target := vm.readUint16(f)
+			if !evaluator.IsTruthy(vm.pop()) {
+				f.ip = target
+			}
+
+		case code.OpJumpTruthy:
+			target := vm.readUint16(f)
+			if evaluator.IsTruthy(vm.pop()) {
+				f.ip = target
+			}
+
+		case code.OpGetGlobal:
+			err = vm.pushVariable(f, f.fn.Globals[vm.readUint16(f)])
+
+		case code.OpSetGlobal:
+			index := vm.readUint16(f)
+			if f.fn.Globals[index] == undefined {
+				err = vm.nameError(f, "Identifier '%s' has not been declared.")
+			} else {
+				f.fn.Globals[index] = vm.pop()
+			}
+
+		case code.OpDefineGlobal:
+			index := vm.readUint16(f)
+			if f.fn.Globals[index] != undefined {
+				err = vm.nameError(f, "Identifier '%s' has already been declared.")
+			} else {
+				f.fn.Globals[index] = vm.pop()
+			}
+
+		case code.OpGetLocal:
+			err = vm.pushVariable(f, vm.stack[f.bp+vm.readUint16(f)])
+
+		case code.OpSetLocal:
+			vm.stack[f.bp+vm.readUint16(f)] = vm.pop()
+
+		case code.OpNewCell:
+			f.cells[vm.readUint16(f)] = &object.Cell{Value: undefined}
+
+		case code.OpGetCell:
+			err = vm.pushVariable(f, f.cells[vm.readUint16(f)].Value)
+
+		case code.OpSetCell:
+			f.cells[vm.readUint16(f)].Value = vm.pop()
+
+		case code.OpGetFree:
+			err = vm.pushVariable(f, f.fn.Free[vm.readUint16(f)].Value)
+
+		case code.OpSetFree:
+			f.fn.Free[vm.readUint16(f)].Value = vm.pop()
+
+		case code.OpClosure:
+			vm.push(vm.closure(f, f.fn.Compiled.Constants[vm.readUint16(f)].(*object.CompiledFunction)))
+
+		case code.OpCall:
+			numArgs := int(ins[f.ip])
+			f.ip++
+
+			err = vm.call(vm.stack[vm.sp-1-numArgs], numArgs)
+
+		case code.OpPipe:
+			fn := vm.pop()
+
+			switch fn.(type) {
+			case *object.Function, *object.BoundMethod, *object.Class, *object.Builtin:
+				arg := vm.pop()
+				vm.push(fn)
+				vm.push(arg)
+				err = vm.call(fn, 1)
+
+			default:
+				err = typeError("right side of pipe operator is not a function: %s", fn.Type())
+			}
+
+		case code.OpReturnValue, code.OpReturn:
+			var result object.Object
+			if op == code.OpReturnValue {
+				result = vm.pop()
+			}
+
+			vm.popFrame()
+
+			if f.instance != nil {
+				result = f.instance
+			}
+
+			if len(vm.frames) == entry {
+				return result
+			}
+
+			vm.push(result)
+
+		case code.OpArray:
+			n := vm.readUint16(f)
+			elements := make([]object.Object, n)
+			copy(elements, vm.stack[vm.sp-n:vm.sp])
+			vm.sp -= n
+
+			array := &object.Array{Elements: elements}
+			if err = vm.runtime.Allocate(array); err == nil {
+				vm.push(array)
+			}
+
+		case code.OpHash:
+			err = vm.buildHash(vm.readUint16(f))
+
+		case code.OpIndex:
+			index := vm.pop()
+			left := vm.pop()
+
+			result := evaluator.Index(left, index)
+			if err = asError(result); err == nil {
+				vm.push(result)
+			}
+
+		case code.OpSetIndex:
+			val := vm.pop()
+			index := vm.pop()
+			left := vm.pop()
+
+			err = asError(evaluator.SetIndex(left, index, val))
+
+		case code.OpMember:
+			name := f.fn.Compiled.Constants[vm.readUint16(f)].(*object.String)
+
+			result := evaluator.Member(vm.pop(), name.Value)
+			if err = asError(result); err == nil {
+				vm.push(result)
+			}
+
+		case code.OpSetMember:
+			name := f.fn.Compiled.Constants[vm.readUint16(f)].(*object.String)
+			val := vm.pop()
+
+			err = asError(evaluator.SetMember(vm.pop(), name.Value, val))
+
+		case code.OpInstance:
+			err = vm.buildInstance(vm.readUint16(f))
+
+		case code.OpClass:
+			name := f.fn.Compiled.Constants[vm.readUint16(f)].(*object.String)
+			numMethods := int(ins[f.ip])
+			hasSuperclass := ins[f.ip+1] == 1
+			f.ip += 2
+
+			err = vm.buildClass(name.Value, numMethods, hasSuperclass)
+
+		case code.OpSuper:
+			name := f.fn.Compiled.Constants[vm.readUint16(f)].(*object.String)
+			self := vm.pop()
+
+			result := evaluator.SuperMethod(vm.pop(), self, name.Value)
+			if err = asError(result); err == nil {
+				vm.push(result)
+			}
+
+		case code.OpInterpolate:
+			n := vm.readUint16(f)
+
+			var out strings.Builder
+			for _, part := range vm.stack[vm.sp-n : vm.sp] {
+				if part != nil {
+					out.WriteString(part.Inspect())
+				}
+			}
+
+			vm.sp -= n
+
+			str := &object.String{Value: out.String()}
+			if err = vm.runtime.Allocate(str); err == nil {
+				vm.push(str)
+			}
+
+		case code.OpIterator:
+			index := vm.readUint16(f)
+			val := vm.pop()
+
+			iterable, ok := val.(object.Iterable)
+			if !ok {
+				err = typeError("not iterable: %s", val.Type())
+				break
+			}
+
+			_, keysOnly := val.(*object.Hash)
+			vm.stack[f.bp+index] = &object.IteratorValue{Iterator: iterable.Iterator(), KeysOnly: keysOnly}
+
+		case code.OpIterNext:
+			iterator := vm.stack[f.bp+vm.readUint16(f)].(*object.IteratorValue)
+			numVars := int(ins[f.ip])
+			target := int(code.ReadUint16(ins[f.ip+1:]))
+			f.ip += 3
+
+			key, value, ok := iterator.Iterator.Next()
+
+			switch {
+			case !ok:
+				f.ip = target
+			case numVars == 2:
+				vm.push(key)
+				vm.push(value)
+			case iterator.KeysOnly:
+				vm.push(key)
+			default:
+				vm.push(value)
+			}
+
+		case code.OpMatchArray:
+			n := vm.readUint16(f)
+			rest := ins[f.ip] == 1
+			f.ip++
+
+			vm.push(nativeBool(evaluator.FitsArray(vm.pop(), n, rest)))
+
+		case code.OpUnpackArray:
+			n := vm.readUint16(f)
+			rest := ins[f.ip] == 1
+			f.ip++
+
+			var elements []object.Object
+			if elements, err = evaluator.UnpackArray(vm.runtime, vm.pop(), n, rest); err == nil {
+				for i := len(elements) - 1; i >= 0; i-- {
+					vm.push(elements[i])
+				}
+			}
+
+		case code.OpUnpackField:
+			name := f.fn.Compiled.Constants[vm.readUint16(f)].(*object.String)
+
+			result := evaluator.UnpackField(vm.pop(), name.Value)
+			if err = asError(result); err == nil {
+				vm.push(result)
+			}
+
+		case code.OpNoMatch:
+			err = evaluator.NoMatch(vm.pop())
+
+		case code.OpTry:
+			target := vm.readUint16(f)
+			vm.handlers = append(vm.handlers, handler{frame: len(vm.frames) - 1, target: target, sp: vm.sp})
+
+		case code.OpEndTry:
+			vm.handlers = vm.handlers[:len(vm.handlers)-1]
+
+		case code.OpThrow:
+			err = evaluator.Throw(vm.pop())
+
+		case code.OpImport:
+			path := f.fn.Compiled.Constants[vm.readUint16(f)].(*object.String)
+			importer := f.fn.Compiled.Constants[vm.readUint16(f)].(*object.String)
+
+			module := vm.importModule(path.Value, importer.Value, f)
+			if err = asError(module); err == nil {
+				vm.push(module)
+			}
+
+		case code.OpImportName:
+			name := f.fn.Compiled.Constants[vm.readUint16(f)].(*object.String)
+			module := vm.pop().(*object.Module)
+
+			val, ok := module.Exports[name.Value]
+			if !ok {
+				err = &object.Error{
+					Message: "module " + module.Name + " has no export " + name.Value,
+					Kind:    object.ImportError,
+				}
+				break
+			}
+
+			vm.push(val)
+
+		default:
+			def, _ := code.Lookup(op)
+			err = &object.Error{Message: "unknown opcode " + def.Name, Kind: object.GenericError}
+		}
+
+		if err != nil && !vm.recover(err, entry) {
+			return err
+		}
+	}
+}
+
+func (vm *VM) push(obj object.Object) {
+	if vm.sp == len(vm.stack) {
+		vm.stack = append(vm.stack, make([]object.Object, len(vm.stack))...)
+	}
+
+	vm.stack[vm.sp] = obj
+	vm.sp++
+}
+
+func (vm *VM) pop() object.Object {
+	vm.sp--
+	return vm.stack[vm.sp]
+}
+
+func (vm *VM) readUint16(f *frame) int {
+	operand := int(code.ReadUint16(f.instructions()[f.ip:]))
+	f.ip += 2
+
+	return operand
+}
+
+// pushVariable pushes the value of a variable, failing if it was not
+// declared yet.
+func (vm *VM) pushVariable(f *frame, val object.Object) *object.Error {
+	if val == undefined {
+		if _, ok := f.fn.Compiled.NodeAt(f.op).(*ast.AssignStatement); ok {
+			return vm.nameError(f, "Identifier '%s' has not been declared.")
+		}
+
+		return vm.nameError(f, "identifier not found: %s")
+	}
+
+	vm.push(val)
+
+	return nil
+}
+
+func (vm *VM) buildHash(numPairs int) *object.Error {
+	hash := object.NewHash()
+	pairs := vm.stack[vm.sp-2*numPairs : vm.sp]
+
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(object.Hashable)
+		if !ok {
+			return typeError("unusable as hash key: %s", pairs[i].Type())
+		}
+
+		hash.Set(key, pairs[i+1])
+	}
+
+	vm.sp -= 2 * numPairs
+	vm.push(hash)
+
+	return nil
+}
+
+// buildInstance replaces a struct and the numFields pairs of field names and
+// values above it with an instance of the struct.
+func (vm *VM) buildInstance(numFields int) *object.Error {
+	pairs := vm.stack[vm.sp-2*numFields : vm.sp]
+	fields := make([]string, numFields)
+	values := make([]object.Object, numFields)
+
+	for i := range fields {
+		fields[i] = pairs[2*i].(*object.String).Value
+		values[i] = pairs[2*i+1]
+	}
+
+	vm.sp -= 2 * numFields
+	instance := evaluator.NewInstance(vm.pop(), fields, values)
+	if err := asError(instance); err != nil {
+		return err
+	}
+
+	vm.push(instance)
+
+	return nil
+}
+
+// buildClass replaces the numMethods pairs of method names and closures on
+// the stack, and the superclass below them if there is one, with a class.
+func (vm *VM) buildClass(name string, numMethods int, hasSuperclass bool) *object.Error {
+	pairs := vm.stack[vm.sp-2*numMethods : vm.sp]
+	methods := make(map[string]*object.Function, numMethods)
+
+	for i := 0; i < len(pairs); i += 2 {
+		methods[pairs[i].(*object.String).Value] = pairs[i+1].(*object.Function)
+	}
+
+	vm.sp -= 2 * numMethods
+
+	var superclass object.Object
+	if hasSuperclass {
+		superclass = vm.pop()
+	}
+
+	class := evaluator.NewClass(name, superclass, methods)
+	if err := asError(class); err != nil {
+		return err
+	}
+
+	vm.push(class)
+
+	return nil
+}
+
+func asError(obj object.Object) *object.Error {
+	err, _ := obj.(*object.Error)
+	return err
+}
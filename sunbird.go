@@ -0,0 +1,278 @@
+// Package sunbird embeds the sunbird interpreter in Go programs.
+//
+// An Interpreter runs programs in a global scope that lasts between runs, so
+// the host can define globals and functions for them, and read back or call
+// what they define:
+//
+//	interp := sunbird.New()
+//	_ = interp.Register("greet", func(name string) string { return "hi " + name })
+//	_, _ = interp.Run(`var double = func(x) { return x * 2 }`)
+//	result, err := interp.Call("double", 21) // int64(42)
+//
+// Go values are converted to sunbird values and back automatically, as
+// described by Set and Get.
+package sunbird
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"sunbird/internal/ast"
+	"sunbird/internal/evaluator"
+	"sunbird/internal/lexer"
+	"sunbird/internal/object"
+	"sunbird/internal/parser"
+	"sunbird/internal/resolver"
+)
+
+// Interpreter runs sunbird programs with the tree walking evaluator. It is not
+// safe for concurrent use.
+type Interpreter struct {
+	resolver *resolver.Resolver
+	env      *object.Environment
+	running  int // the runs and calls in progress, nested ones included
+}
+
+// New creates an interpreter with an empty global scope.
+func New() *Interpreter {
+	return &Interpreter{
+		resolver: resolver.New(evaluator.IsBuiltin),
+		env:      object.NewEnvironment(),
+	}
+}
+
+// SetStdin sets the reader the input and readline builtins read from, which
+// is the standard input of the process by default.
+func (i *Interpreter) SetStdin(r io.Reader) {
+	i.env.Runtime().Stdin = r
+}
+
+// SetStdout sets the writer the print and println builtins write to, which
+// is the standard output of the process by default.
+func (i *Interpreter) SetStdout(w io.Writer) {
+	i.env.Runtime().Stdout = w
+}
+
+// SetStderr sets the writer the eprintln builtin writes to, which is the
+// standard error of the process by default.
+func (i *Interpreter) SetStderr(w io.Writer) {
+	i.env.Runtime().Stderr = w
+}
+
+// Limits bound the resources of every run and call. Zero fields are
+// unlimited, except MaxDepth which then keeps the default of 10000 calls.
+// Exceeding MaxDepth raises a RecursionError programs can catch, exceeding
+// the other limits stops the program with a LimitError.
+type Limits struct {
+	MaxSteps int64 // evaluation steps, about one per expression or statement
+	MaxDepth int   // nested function calls
+	MaxAlloc int64 // approximate bytes allocated for strings and arrays
+}
+
+// SetLimits sets the limits of the runs and calls that follow.
+func (i *Interpreter) SetLimits(limits Limits) {
+	if limits.MaxDepth == 0 {
+		limits.MaxDepth = object.DefaultMaxDepth
+	}
+
+	i.env.Runtime().Limits = object.Limits(limits)
+}
+
+// Run runs src in the global scope and returns the value of its last
+// statement, converted like Get converts globals. Syntax errors, undeclared
+// variables and runtime errors are returned as *Error, or joined together when
+// there are several. The globals of a program that fails are not declared.
+func (i *Interpreter) Run(src string) (interface{}, error) {
+	return i.RunContext(context.Background(), src)
+}
+
+// RunContext is like Run, but stops the program with a LimitError when ctx is
+// done. Called from a registered function while a program runs, it keeps the
+// context of that program instead.
+func (i *Interpreter) RunContext(ctx context.Context, src string) (interface{}, error) {
+	defer i.start(ctx)()
+
+	p := parser.New(lexer.New("", src))
+	program := p.ParseProgram()
+
+	if diagnostics := p.Diagnostics(); len(diagnostics) != 0 {
+		return nil, syntaxErrors(diagnostics)
+	}
+
+	if errs := i.resolver.Resolve(program); len(errs) != 0 {
+		return nil, runtimeErrors(errs)
+	}
+
+	evaluated, err := i.eval(program)
+	if err != nil || evaluated == nil {
+		return nil, err
+	}
+
+	return toGo(i.env.Runtime(), evaluated)
+}
+
+// eval evaluates a resolved program. Like for the errors the resolver finds,
+// nothing a program that fails declares is kept.
+func (i *Interpreter) eval(program *ast.Program) (_ object.Object, err error) {
+	defer func() {
+		if err != nil {
+			i.resolver.Rollback()
+		}
+	}()
+
+	defer recoverPanic(&err)
+
+	evaluated := evaluator.Eval(program, i.env)
+	if err, ok := evaluated.(*object.Error); ok {
+		return nil, newError(err)
+	}
+
+	return evaluated, nil
+}
+
+// Set declares the global name, or overwrites it, with value converted to a
+// sunbird value:
+//
+//   - nil is null, and bools, strings, integers and floats are the matching
+//     scalars; every integer type becomes an integer
+//   - slices and arrays are arrays
+//   - maps are hashes, and so are structs, keyed by the names of their
+//     exported fields or by their `sunbird:"name"` tag; the tag "-" leaves a
+//     field out
+//   - pointers and interfaces are converted through the value they hold
+//   - functions become builtins, as described by Register
+//
+// Other values are an error.
+func (i *Interpreter) Set(name string, value interface{}) error {
+	obj, err := fromGo(reflect.ValueOf(value))
+	if err != nil {
+		return fmt.Errorf("cannot set %s: %w", name, err)
+	}
+
+	i.env.Set(0, i.resolver.DeclareGlobal(name), obj)
+
+	return nil
+}
+
+// Get returns the global name converted to a Go value: null is nil, integers
+// are int64, floats are float64, arrays are []interface{}, hashes and the
+// instances of structs and classes are map[string]interface{} and functions,
+// methods and classes are func(...interface{}) (interface{}, error). A value
+// containing itself has the string "[...]" or "{...}" where it appears again,
+// like programs print it. The result is false when the global is not declared,
+// or is a hash with two keys shown the same, like 1 and "1", which Go cannot
+// tell apart.
+func (i *Interpreter) Get(name string) (interface{}, bool) {
+	obj, ok := i.global(name)
+	if !ok {
+		return nil, false
+	}
+
+	value, err := toGo(i.env.Runtime(), obj)
+	if err != nil {
+		return nil, false
+	}
+
+	return value, true
+}
+
+// Call calls the global function or builtin name with args, converted like
+// Set converts values, and returns its result converted like Get converts
+// globals.
+func (i *Interpreter) Call(name string, args ...interface{}) (interface{}, error) {
+	return i.CallContext(context.Background(), name, args...)
+}
+
+// CallContext is like Call, but stops the function with a LimitError when ctx
+// is done. Called from a registered function while a program runs, it keeps
+// the context of that program instead.
+func (i *Interpreter) CallContext(ctx context.Context, name string, args ...interface{}) (interface{}, error) {
+	defer i.start(ctx)()
+
+	fn, ok := i.global(name)
+	if !ok {
+		return nil, fmt.Errorf("identifier not found: %s", name)
+	}
+
+	switch fn.Type() {
+	case object.FunctionObj, object.BuiltinObj, object.BoundMethodObj, object.ClassObj:
+	default:
+		return nil, fmt.Errorf("not a function: %s", fn.Type())
+	}
+
+	return callFunction(i.env.Runtime(), fn, args)
+}
+
+// Register declares the global name as a builtin calling fn, which must be a
+// function. Its arguments are converted to the types of the parameters of fn,
+// and its results to sunbird values: no result is null, and several results
+// are an array. A last result of type error is not part of the results but
+// raises a runtime error when it is not nil.
+func (i *Interpreter) Register(name string, fn interface{}) error {
+	if reflect.TypeOf(fn) == nil || reflect.TypeOf(fn).Kind() != reflect.Func {
+		return fmt.Errorf("cannot register %s: %T is not a function", name, fn)
+	}
+
+	return i.Set(name, fn)
+}
+
+// start starts a run or call, returning the function that ends it. Only the
+// outermost one resets the runtime: runs and calls made by a registered
+// function while a program runs count against the limits and context of that
+// program.
+func (i *Interpreter) start(ctx context.Context) func() {
+	rt := i.env.Runtime()
+
+	i.running++
+	if i.running == 1 {
+		rt.Reset(ctx)
+	}
+
+	return func() {
+		i.running--
+		if i.running == 0 {
+			rt.Reset(nil)
+		}
+	}
+}
+
+// global returns the global name, falling back to the builtin name like
+// programs do.
+func (i *Interpreter) global(name string) (object.Object, bool) {
+	if slot, ok := i.resolver.Global(name); ok {
+		return i.env.Get(0, slot)
+	}
+
+	return evaluator.LookupBuiltin(name)
+}
+
+// callFunction calls a sunbird function with Go arguments, in the runtime rt.
+func callFunction(rt *object.Runtime, fn object.Object, args []interface{}) (_ interface{}, err error) {
+	defer recoverPanic(&err)
+
+	objs := make([]object.Object, len(args))
+	for n, arg := range args {
+		obj, err := fromGo(reflect.ValueOf(arg))
+		if err != nil {
+			return nil, fmt.Errorf("argument %d: %w", n+1, err)
+		}
+
+		objs[n] = obj
+	}
+
+	result := evaluator.Call(rt, fn, objs...)
+	if err, ok := result.(*object.Error); ok {
+		return nil, newError(err)
+	}
+
+	return toGo(rt, result)
+}
+
+// recoverPanic turns a panic of the interpreter, or of a Go function it
+// called, into the error of the run or call.
+func recoverPanic(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("internal error: %v", r)
+	}
+}
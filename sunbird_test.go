@@ -0,0 +1,477 @@
+package sunbird_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sunbird"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"1 + 2", int64(3)},
+		{"1.5 * 2", 3.0},
+		{`"sun" + "bird"`, "sunbird"},
+		{"1 < 2", true},
+		{"null", nil},
+		{"[1, [2.5], \"a\"]", []interface{}{int64(1), []interface{}{2.5}, "a"}},
+		{`{"a": 1, 2: true}`, map[string]interface{}{"a": int64(1), "2": true}},
+		{"struct P { x, y }; P{x: 1}", map[string]interface{}{"x": int64(1), "y": nil}},
+		{"class C { init() { self.n = 1 } }; C()", map[string]interface{}{"n": int64(1)}},
+		{"var x = 1", nil},
+		{"var a = [1, 0]; a[1] = a; a", []interface{}{int64(1), "[...]"}},
+		{`var h = {}; h["h"] = h; h`, map[string]interface{}{"h": "{...}"}},
+	}
+
+	for _, tt := range tests {
+		result, err := sunbird.New().Run(tt.input)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", tt.input, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("%q: wrong result. got=%#v, want=%#v", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestRunKeepsGlobals(t *testing.T) {
+	interp := sunbird.New()
+
+	if _, err := interp.Run("var count = 1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	result, err := interp.Run("count = count + 1\ncount")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if result != int64(2) {
+		t.Errorf("wrong result. got=%#v, want=2", result)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 +", "1:4: SyntaxError: "},
+		{"missing", "1:1: NameError: identifier not found: missing"},
+		{"\n1 + true", "2:1: TypeError: type mismatch"},
+		{`throw "boom"`, "1:1: Error: boom"},
+	}
+
+	for _, tt := range tests {
+		_, err := sunbird.New().Run(tt.input)
+
+		var serr *sunbird.Error
+		if !errors.As(err, &serr) {
+			t.Errorf("%q: expected *sunbird.Error, got=%#v", tt.input, err)
+			continue
+		}
+
+		if !strings.HasPrefix(err.Error(), tt.expected) {
+			t.Errorf("%q: wrong error. got=%q, want prefix %q", tt.input, err, tt.expected)
+		}
+	}
+}
+
+func TestFailedRunDeclaresNothing(t *testing.T) {
+	interp := sunbird.New()
+
+	if _, err := interp.Run("var b = 1 / 0"); err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	if _, ok := interp.Get("b"); ok {
+		t.Errorf("b is declared after the failed run")
+	}
+
+	if _, err := interp.Run("var b = 2"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if result, ok := interp.Get("b"); !ok || result != int64(2) {
+		t.Errorf("wrong result. got=%#v, %t, want=2", result, ok)
+	}
+}
+
+func TestPanicsAreErrors(t *testing.T) {
+	interp := sunbird.New()
+
+	if err := interp.Register("crash", func() { panic("oops") }); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := interp.Run("var before = 1; crash()"); err == nil || err.Error() != "internal error: oops" {
+		t.Errorf("wrong error. got=%v", err)
+	}
+
+	if _, ok := interp.Get("before"); ok {
+		t.Errorf("before is declared after the failed run")
+	}
+
+	if _, err := interp.Call("crash"); err == nil || err.Error() != "internal error: oops" {
+		t.Errorf("wrong error. got=%v", err)
+	}
+
+	// the calls the panics unwound do not count against later runs
+	interp.SetLimits(sunbird.Limits{MaxDepth: 5})
+
+	if _, err := interp.Run("var f = func() { crash() }; var g = func() { 1 }"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for n := 0; n < 10; n++ {
+		if _, err := interp.Call("f"); err == nil || err.Error() != "internal error: oops" {
+			t.Errorf("wrong error. got=%v", err)
+		}
+	}
+
+	if result, err := interp.Call("g"); err != nil || result != int64(1) {
+		t.Errorf("wrong result. got=%#v, %v", result, err)
+	}
+}
+
+func TestInputOutput(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	interp := sunbird.New()
+	interp.SetStdin(strings.NewReader("ada\nturing\n"))
+	interp.SetStdout(&stdout)
+	interp.SetStderr(&stderr)
+
+	result, err := interp.Run(`println(input("first? ")); eprintln("warning"); readline()`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if result != "turing" || stdout.String() != "first? ada \n" || stderr.String() != "warning \n" {
+		t.Errorf("wrong result. got=%#v, %q, %q", result, stdout.String(), stderr.String())
+	}
+}
+
+func TestLimits(t *testing.T) {
+	interp := sunbird.New()
+	interp.SetLimits(sunbird.Limits{MaxSteps: 10000})
+
+	if _, err := interp.Run("var spin = func() { while (true) {} }"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// every run gets the whole step budget
+	for n := 0; n < 2; n++ {
+		_, err := interp.Call("spin")
+		if err == nil || !strings.HasSuffix(err.Error(), "LimitError: step limit of 10000 exceeded") {
+			t.Errorf("wrong error. got=%v", err)
+		}
+	}
+
+	interp.SetLimits(sunbird.Limits{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := interp.RunContext(ctx, "spin()")
+	if err == nil || !strings.HasSuffix(err.Error(), "LimitError: execution cancelled: context deadline exceeded") {
+		t.Errorf("wrong error. got=%v", err)
+	}
+
+	result, err := interp.Run("var depth = func(n) { if (n == 0) { return 0 } return depth(n - 1) }; depth(5000)")
+	if err != nil || result != int64(0) {
+		t.Errorf("wrong result. got=%#v, %v", result, err)
+	}
+}
+
+func TestNestedCallsKeepTheLimits(t *testing.T) {
+	interp := sunbird.New()
+
+	if _, err := interp.Run("var one = func() { 1 }"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := interp.Register("cb", func() { _, _ = interp.Call("one") }); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	interp.SetLimits(sunbird.Limits{MaxSteps: 1000})
+
+	_, err := interp.Run("for i in range(0, 100000) { cb() }")
+	if err == nil || !strings.HasSuffix(err.Error(), "LimitError: step limit of 1000 exceeded") {
+		t.Errorf("wrong error. got=%v", err)
+	}
+
+	interp.SetLimits(sunbird.Limits{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = interp.RunContext(ctx, "while true { cb() }")
+	if err == nil || !strings.HasSuffix(err.Error(), "LimitError: execution cancelled: context deadline exceeded") {
+		t.Errorf("wrong error. got=%v", err)
+	}
+}
+
+func TestErrorStack(t *testing.T) {
+	interp := sunbird.New()
+
+	_, err := interp.Run("var f = func() { 1 / 0 }\nf()")
+
+	var serr *sunbird.Error
+	if !errors.As(err, &serr) {
+		t.Fatalf("expected *sunbird.Error, got=%#v", err)
+	}
+
+	expected := []sunbird.Frame{{Function: "f", Line: 2, Column: 1}}
+	if serr.Kind != "ZeroDivisionError" || !reflect.DeepEqual(serr.Stack, expected) {
+		t.Errorf("wrong error. got=%+v", serr)
+	}
+}
+
+type point struct {
+	X      int
+	Y      int     `sunbird:"y"`
+	Hidden string  `sunbird:"-"`
+	Scale  float64 `sunbird:"scale"`
+	label  string
+}
+
+func TestSetAndGet(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		script   string
+		expected interface{}
+	}{
+		{42, "v + 1", int64(43)},
+		{uint8(7), "v", int64(7)},
+		{float32(0.5), "v", 0.5},
+		{"hi", "v + \"!\"", "hi!"},
+		{true, "!v", false},
+		{nil, "v", nil},
+		{[]int{1, 2, 3}, "len(v)", int64(3)},
+		{[2]string{"a", "b"}, "v[1]", "b"},
+		{map[string]int{"b": 2, "a": 1}, "keys(v)", []interface{}{"a", "b"}},
+		{map[int]bool{1: true}, "v[1]", true},
+		{point{X: 1, Y: 2, Scale: 1.5, label: "p"}, "v", map[string]interface{}{"X": int64(1), "y": int64(2), "scale": 1.5}},
+		{&point{X: 3}, `v["X"]`, int64(3)},
+		{[]interface{}{1, "a", nil}, "v", []interface{}{int64(1), "a", nil}},
+	}
+
+	for _, tt := range tests {
+		interp := sunbird.New()
+
+		if err := interp.Set("v", tt.value); err != nil {
+			t.Errorf("%#v: unexpected error: %s", tt.value, err)
+			continue
+		}
+
+		result, err := interp.Run(tt.script)
+		if err != nil {
+			t.Errorf("%#v: unexpected error: %s", tt.value, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("%#v: wrong result. got=%#v, want=%#v", tt.value, result, tt.expected)
+		}
+	}
+}
+
+func TestSetOverwritesAndGetReadsScriptGlobals(t *testing.T) {
+	interp := sunbird.New()
+
+	if _, ok := interp.Get("x"); ok {
+		t.Errorf("expected x to be undeclared")
+	}
+
+	if _, err := interp.Run("var x = [1, 2]"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if x, ok := interp.Get("x"); !ok || !reflect.DeepEqual(x, []interface{}{int64(1), int64(2)}) {
+		t.Errorf("wrong x. got=%#v, %v", x, ok)
+	}
+
+	if err := interp.Set("x", "changed"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if result, err := interp.Run("x"); err != nil || result != "changed" {
+		t.Errorf("wrong x. got=%#v, %v", result, err)
+	}
+}
+
+func TestSetUnsupported(t *testing.T) {
+	err := sunbird.New().Set("c", make(chan int))
+	if err == nil || err.Error() != "cannot set c: unsupported type chan int" {
+		t.Errorf("wrong error. got=%v", err)
+	}
+}
+
+func TestSetOverflow(t *testing.T) {
+	err := sunbird.New().Set("big", uint64(1<<63+5))
+	if err == nil || err.Error() != "cannot set big: 9223372036854775813 overflows integer" {
+		t.Errorf("wrong error. got=%v", err)
+	}
+}
+
+func TestCollidingHashKeys(t *testing.T) {
+	interp := sunbird.New()
+
+	_, err := interp.Run(`var h = {1: "a", "1": "b"}; h`)
+	if err == nil || err.Error() != `hash has several keys shown as "1"` {
+		t.Errorf("wrong error. got=%v", err)
+	}
+
+	if _, ok := interp.Get("h"); ok {
+		t.Errorf("h converted although two of its keys collide")
+	}
+}
+
+func TestCall(t *testing.T) {
+	interp := sunbird.New()
+
+	_, err := interp.Run(`
+var add = func(a, b) { return a + b }
+var first = func(p) { return p["X"] }
+var fail = func() { throw "nope" }
+var notFunc = 1
+var empty = func() {}
+var declares = func() { var x = 1 }
+class Pair { init(a, b) { self.a = a; self.b = b } }
+`)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tests := []struct {
+		name     string
+		args     []interface{}
+		expected interface{}
+		err      string
+	}{
+		{"add", []interface{}{1, 2}, int64(3), ""},
+		{"add", []interface{}{"a", "b"}, "ab", ""},
+		{"first", []interface{}{point{X: 9}}, int64(9), ""},
+		{"len", []interface{}{"four"}, int64(4), ""},
+		{"add", []interface{}{1}, nil, "TypeError: wrong number of arguments: expected 2, got 1"},
+		{"fail", nil, nil, "4:21: Error: nope"},
+		{"missing", nil, nil, "identifier not found: missing"},
+		{"notFunc", nil, nil, "not a function: INTEGER"},
+		{"empty", nil, nil, ""},
+		{"declares", nil, nil, ""},
+		{"Pair", []interface{}{1, "x"}, map[string]interface{}{"a": int64(1), "b": "x"}, ""},
+	}
+
+	for _, tt := range tests {
+		result, err := interp.Call(tt.name, tt.args...)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("%s: wrong error. got=%v, want=%q", tt.name, err, tt.err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("%s: wrong result. got=%#v, want=%#v", tt.name, result, tt.expected)
+		}
+	}
+}
+
+func TestGetFunction(t *testing.T) {
+	interp := sunbird.New()
+
+	if _, err := interp.Run("var twice = func(x) { return x * 2 }"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	twice, _ := interp.Get("twice")
+
+	fn, ok := twice.(func(...interface{}) (interface{}, error))
+	if !ok {
+		t.Fatalf("expected a function, got=%T", twice)
+	}
+
+	if result, err := fn(1.5); err != nil || result != 3.0 {
+		t.Errorf("wrong result. got=%#v, %v", result, err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		fn       interface{}
+		script   string
+		expected interface{}
+		err      string
+	}{
+		{func(a, b int) int { return a + b }, "f(1, 2)", int64(3), ""},
+		{func(s string) string { return strings.ToUpper(s) }, `f("a")`, "A", ""},
+		{func(x float64) float64 { return x / 2 }, "f(3)", 1.5, ""},
+		{func() {}, "f()", nil, ""},
+		{func(n int) (int, int) { return n, -n }, "f(1)", []interface{}{int64(1), int64(-1)}, ""},
+		{func(xs ...int) int { return len(xs) }, "f(1, 2, 3)", int64(3), ""},
+		{func(sep string, xs ...string) string { return strings.Join(xs, sep) }, `f("-", "a", "b")`, "a-b", ""},
+		{func(xs []string, m map[string]int) int { return len(xs) + m["a"] }, `f(["x"], {"a": 2})`, int64(3), ""},
+		{func(p point) int { return p.X + p.Y }, `f({"X": 1, "y": 2})`, int64(3), ""},
+		{func(p point) int { return p.X + p.Y }, `struct P { X, y }; f(P{X: 1, y: 2})`, int64(3), ""},
+		{func(p point) int { return p.X + p.Y }, `class P { init() { self.X = 1; self.y = 2 } }; f(P())`, int64(3), ""},
+		{func(p *point) bool { return p == nil }, "f(null)", true, ""},
+		{func(v interface{}) interface{} { return v }, "f([1])", []interface{}{int64(1)}, ""},
+		{func(b bool) (string, error) { return "ok", nil }, "f(true)", "ok", ""},
+		{func() error { return fmt.Errorf("failed") }, "f()", nil, "1:1: Error: failed"},
+		{func(a int) int { return a }, "f()", nil, "TypeError: wrong number of arguments. got=0, want=1"},
+		{func(a int8) int8 { return a }, "f(300)", nil, "TypeError: argument 1: 300 overflows int8"},
+		{func(a int) int { return a }, `f("1")`, nil, "TypeError: argument 1: cannot use STRING as int"},
+	}
+
+	for _, tt := range tests {
+		interp := sunbird.New()
+
+		if err := interp.Register("f", tt.fn); err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.script, err)
+			continue
+		}
+
+		result, err := interp.Run(tt.script)
+		if tt.err != "" {
+			if err == nil || !strings.HasSuffix(err.Error(), tt.err) {
+				t.Errorf("%s: wrong error. got=%v, want=%q", tt.script, err, tt.err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", tt.script, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("%s: wrong result. got=%#v, want=%#v", tt.script, result, tt.expected)
+		}
+	}
+}
+
+func TestRegisterNotAFunction(t *testing.T) {
+	err := sunbird.New().Register("f", 1)
+	if err == nil || err.Error() != "cannot register f: int is not a function" {
+		t.Errorf("wrong error. got=%v", err)
+	}
+}